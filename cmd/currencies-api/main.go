@@ -9,9 +9,10 @@ import (
 	"syscall"
 
 	"github.com/alemax1/currencies-api/config"
-	forex "github.com/alemax1/currencies-api/internal/currency/adapter/forexApi"
 	"github.com/alemax1/currencies-api/internal/currency/adapter/postgres"
+	"github.com/alemax1/currencies-api/internal/currency/adapter/providers"
 	"github.com/alemax1/currencies-api/internal/currency/delivery/http/handler"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
 	"github.com/alemax1/currencies-api/internal/currency/service"
 	"github.com/alemax1/currencies-api/pkg/logger"
 	"github.com/alemax1/currencies-api/pkg/pgdb"
@@ -60,6 +61,10 @@ func run(cfgPath string) {
 		l.Fatal().Msgf("init config: %v", err)
 	}
 
+	if !domain.RoundingMode(cfg.Conversion.RoundingMode).IsValid() {
+		l.Fatal().Msgf("invalid rounding mode: %s", cfg.Conversion.RoundingMode)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Postgres.PingTimeout)
 	defer cancel()
 	db, err := pgdb.Open(ctx, cfg.Postgres.ToDSN())
@@ -68,15 +73,32 @@ func run(cfgPath string) {
 	}
 
 	executor := postgres.NewExecutor(db)
-	currencyRepo := postgres.NewCurrency(executor)
-	forexApi := forex.New(cfg.CurrenciesAPI)
+	repos := service.Repos{
+		CurrencyRepo:   postgres.NewCurrency(executor),
+		HistoryRepo:    postgres.NewHistory(executor),
+		PairPolicyRepo: postgres.NewPairPolicy(executor),
+		QuoteRepo:      postgres.NewQuote(executor),
+		SpreadRepo:     postgres.NewSpread(executor),
+		FeeRepo:        postgres.NewFee(executor),
+		OverrideRepo:   postgres.NewOverride(executor),
+		HealthRepo:     postgres.NewProviderHealth(executor),
+		QuotaRepo:      postgres.NewQuota(executor),
+		SymbolRepo:     postgres.NewProviderSymbol(executor),
+		PegRepo:        postgres.NewPeg(executor),
+	}
+
+	registry, err := providers.New(cfg.Providers, repos.SymbolRepo, l)
+	if err != nil {
+		l.Fatal().Msgf("init providers: %v", err)
+	}
 
-	service := service.New(currencyRepo, forexApi, l)
+	service := service.New(repos, registry, cfg.Conversion, l)
+	service.WarmUp()
 
 	app := fiber.New()
 	app.Use(handler.TimeoutMiddleware(cfg.Handler.RequestTimeout))
 
-	handler := handler.New(service, l)
+	handler := handler.New(service, cfg.Handler, l)
 	handler.InitRoutes(app)
 
 	go func() {
@@ -5,19 +5,28 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/alemax1/currencies-api/config"
 	worker "github.com/alemax1/currencies-api/internal/currencies-worker"
-	forex "github.com/alemax1/currencies-api/internal/currency/adapter/forexApi"
 	"github.com/alemax1/currencies-api/internal/currency/adapter/postgres"
+	"github.com/alemax1/currencies-api/internal/currency/adapter/providers"
 	"github.com/alemax1/currencies-api/internal/currency/service"
 	"github.com/alemax1/currencies-api/pkg/logger"
 	"github.com/alemax1/currencies-api/pkg/pgdb"
 	"github.com/spf13/cobra"
 )
 
-const configFlagName = "config"
+const (
+	configFlagName   = "config"
+	fromFlagName     = "from"
+	toFlagName       = "to"
+	currencyFlagName = "currency"
+
+	backfillTimeout = time.Hour
+)
 
 func main() {
 	rootCmd := &cobra.Command{
@@ -33,7 +42,59 @@ func main() {
 		},
 	}
 
-	rootCmd.Flags().StringP(configFlagName, "c", ".env", "config file path")
+	backfillCmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "load historical daily rates of a currency into history",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfgPath, err := cmd.Flags().GetString(configFlagName)
+			if err != nil {
+				log.Fatalf("get flag value: %v", err)
+			}
+
+			fromValue, err := cmd.Flags().GetString(fromFlagName)
+			if err != nil {
+				log.Fatalf("get flag value: %v", err)
+			}
+
+			toValue, err := cmd.Flags().GetString(toFlagName)
+			if err != nil {
+				log.Fatalf("get flag value: %v", err)
+			}
+
+			currency, err := cmd.Flags().GetString(currencyFlagName)
+			if err != nil {
+				log.Fatalf("get flag value: %v", err)
+			}
+
+			from, err := time.Parse(time.DateOnly, fromValue)
+			if err != nil {
+				log.Fatalf("parse from: %v", err)
+			}
+
+			to, err := time.Parse(time.DateOnly, toValue)
+			if err != nil {
+				log.Fatalf("parse to: %v", err)
+			}
+
+			if to.Before(from) || to.After(time.Now()) {
+				log.Fatalf("invalid range: from must not be after to and to must not be in the future")
+			}
+
+			runBackfill(cfgPath, strings.ToUpper(currency), from, to)
+		},
+	}
+
+	backfillCmd.Flags().String(fromFlagName, "", "first day to backfill (YYYY-MM-DD)")
+	backfillCmd.Flags().String(toFlagName, "", "last day to backfill (YYYY-MM-DD)")
+	backfillCmd.Flags().String(currencyFlagName, "", "currency name")
+	for _, name := range []string{fromFlagName, toFlagName, currencyFlagName} {
+		if err := backfillCmd.MarkFlagRequired(name); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	rootCmd.PersistentFlags().StringP(configFlagName, "c", ".env", "config file path")
+	rootCmd.AddCommand(backfillCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
@@ -41,6 +102,34 @@ func main() {
 }
 
 func run(cfgPath string) {
+	cfg, l, service := setup(cfgPath)
+
+	service.WarmUp()
+
+	worker := worker.New(service.Currency, cfg.CurrenciesWorker, l)
+	worker.Run()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	<-quit
+
+	worker.Stop()
+}
+
+func runBackfill(cfgPath, currency string, from, to time.Time) {
+	_, l, service := setup(cfgPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), backfillTimeout)
+	defer cancel()
+
+	inserted, err := service.Currency.Backfill(ctx, currency, from, to)
+	if err != nil {
+		l.Fatal().Msgf("backfill %s: %v", currency, err)
+	}
+
+	l.Info().Msgf("backfill finished, name:%s, rows:%d", currency, inserted)
+}
+
+func setup(cfgPath string) (*config.Config, logger.Logger, *service.Service) {
 	l, err := logger.New()
 	if err != nil {
 		log.Fatalf("init logger: %v", err)
@@ -51,6 +140,10 @@ func run(cfgPath string) {
 		l.Fatal().Msgf("init config: %v", err)
 	}
 
+	if err := worker.RetentionPolicy(cfg.CurrenciesWorker).Validate(); err != nil {
+		l.Fatal().Msgf("invalid retention policy: %v", err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Postgres.PingTimeout)
 	defer cancel()
 	db, err := pgdb.Open(ctx, cfg.Postgres.ToDSN())
@@ -59,17 +152,24 @@ func run(cfgPath string) {
 	}
 
 	executor := postgres.NewExecutor(db)
-	currencyRepo := postgres.NewCurrency(executor)
-
-	forexApi := forex.New(cfg.CurrenciesAPI)
-
-	service := service.New(currencyRepo, forexApi, l)
+	repos := service.Repos{
+		CurrencyRepo:   postgres.NewCurrency(executor),
+		HistoryRepo:    postgres.NewHistory(executor),
+		PairPolicyRepo: postgres.NewPairPolicy(executor),
+		QuoteRepo:      postgres.NewQuote(executor),
+		SpreadRepo:     postgres.NewSpread(executor),
+		FeeRepo:        postgres.NewFee(executor),
+		OverrideRepo:   postgres.NewOverride(executor),
+		HealthRepo:     postgres.NewProviderHealth(executor),
+		QuotaRepo:      postgres.NewQuota(executor),
+		SymbolRepo:     postgres.NewProviderSymbol(executor),
+		PegRepo:        postgres.NewPeg(executor),
+	}
 
-	worker := worker.New(service.Currency, cfg.CurrenciesWorker, l)
-	worker.Run()
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-quit
+	registry, err := providers.New(cfg.Providers, repos.SymbolRepo, l)
+	if err != nil {
+		l.Fatal().Msgf("init providers: %v", err)
+	}
 
-	worker.Stop()
+	return cfg, l, service.New(repos, registry, cfg.Conversion, l)
 }
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,9 +13,11 @@ import (
 type Config struct {
 	Postgres         Postgres
 	CurrenciesAPI    CurrenciesAPI
+	Providers        Providers
 	Handler          Handler
 	CurrenciesWorker CurrenciesWorker
 	Server           Server
+	Conversion       Conversion
 }
 
 func New(cfgPath string) (*Config, error) {
@@ -25,9 +28,11 @@ func New(cfgPath string) (*Config, error) {
 	return &Config{
 		Postgres:         newPostgres(),
 		CurrenciesAPI:    newCurrenciesAPI(),
+		Providers:        newProviders(),
 		Handler:          newHandler(),
 		CurrenciesWorker: newCurrenciesWorker(),
 		Server:           newServer(),
+		Conversion:       newConversion(),
 	}, nil
 }
 
@@ -54,6 +59,24 @@ func getDefaultIntEnv(key string, defaultValue int) int {
 	return val
 }
 
+// getDefaultListEnv splits a comma separated value, skipping empty items.
+func getDefaultListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
 func getDefaultDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
 	if value == "" {
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+type Conversion struct {
+	RoundingMode string
+	QuoteTTL     time.Duration
+}
+
+func newConversion() Conversion {
+	return Conversion{
+		RoundingMode: getDefaultEnv("CONVERSION_ROUNDING_MODE", "half_even"),
+		QuoteTTL:     getDefaultDurationEnv("CONVERSION_QUOTE_TTL", 30*time.Second),
+	}
+}
@@ -1,15 +1,46 @@
 package config
 
+import "time"
+
 type CurrenciesAPI struct {
 	APIKey        string
 	FetchMultiURL string
 	FetchOneURL   string
+	TimeSeriesURL string
+	// Timeout bounds a single request so a hanging provider leaves time for the fallback ones.
+	Timeout time.Duration
+	// BreakerFailures consecutive failures open the circuit breaker of the provider
+	// for BreakerOpenTimeout, after which a single probe request is let through.
+	BreakerFailures    int
+	BreakerOpenTimeout time.Duration
+	// RetryAttempts counts the first request, the delay between attempts grows
+	// exponentially from RetryBaseDelay up to RetryMaxDelay.
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// DailyBudget and MonthlyBudget limit the calls billed by the provider, zero means unlimited.
+	DailyBudget   int
+	MonthlyBudget int
 }
 
 func newCurrenciesAPI() CurrenciesAPI {
+	return newCurrenciesAPIWithPrefix("CURRENCIES_API_")
+}
+
+// newCurrenciesAPIWithPrefix reads the provider settings from the variables starting with prefix.
+func newCurrenciesAPIWithPrefix(prefix string) CurrenciesAPI {
 	return CurrenciesAPI{
-		APIKey:        getDefaultEnv("CURRENCIES_API_KEY", ""),
-		FetchMultiURL: getDefaultEnv("CURRENCIES_API_FETCH_MULTI_URL", ""),
-		FetchOneURL:   getDefaultEnv("CURRENCIES_API_FETCH_ONE_URL", ""),
+		APIKey:             getDefaultEnv(prefix+"KEY", ""),
+		FetchMultiURL:      getDefaultEnv(prefix+"FETCH_MULTI_URL", ""),
+		FetchOneURL:        getDefaultEnv(prefix+"FETCH_ONE_URL", ""),
+		TimeSeriesURL:      getDefaultEnv(prefix+"TIME_SERIES_URL", ""),
+		Timeout:            getDefaultDurationEnv(prefix+"TIMEOUT", 10*time.Second),
+		BreakerFailures:    getDefaultIntEnv(prefix+"BREAKER_FAILURES", 5),
+		BreakerOpenTimeout: getDefaultDurationEnv(prefix+"BREAKER_OPEN_TIMEOUT", 1*time.Minute),
+		RetryAttempts:      getDefaultIntEnv(prefix+"RETRY_ATTEMPTS", 3),
+		RetryBaseDelay:     getDefaultDurationEnv(prefix+"RETRY_BASE_DELAY", 200*time.Millisecond),
+		RetryMaxDelay:      getDefaultDurationEnv(prefix+"RETRY_MAX_DELAY", 5*time.Second),
+		DailyBudget:        getDefaultIntEnv(prefix+"DAILY_BUDGET", 0),
+		MonthlyBudget:      getDefaultIntEnv(prefix+"MONTHLY_BUDGET", 0),
 	}
 }
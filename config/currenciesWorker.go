@@ -3,11 +3,19 @@ package config
 import "time"
 
 type CurrenciesWorker struct {
-	IterationTimeout time.Duration
+	IterationTimeout       time.Duration
+	MaintenanceInterval    time.Duration
+	HistoryDownsampleAfter time.Duration
+	HistoryRawRetention    time.Duration
+	HistoryHourlyRetention time.Duration
 }
 
 func newCurrenciesWorker() CurrenciesWorker {
 	return CurrenciesWorker{
-		IterationTimeout: getDefaultDurationEnv("CURRENCIES_WORKER_ITERATION_TIMEOUT", 1*time.Minute),
+		IterationTimeout:       getDefaultDurationEnv("CURRENCIES_WORKER_ITERATION_TIMEOUT", 1*time.Minute),
+		MaintenanceInterval:    getDefaultDurationEnv("CURRENCIES_WORKER_MAINTENANCE_INTERVAL", 1*time.Hour),
+		HistoryDownsampleAfter: getDefaultDurationEnv("CURRENCIES_WORKER_HISTORY_DOWNSAMPLE_AFTER", 24*time.Hour),
+		HistoryRawRetention:    getDefaultDurationEnv("CURRENCIES_WORKER_HISTORY_RAW_RETENTION", 7*24*time.Hour),
+		HistoryHourlyRetention: getDefaultDurationEnv("CURRENCIES_WORKER_HISTORY_HOURLY_RETENTION", 90*24*time.Hour),
 	}
 }
@@ -4,10 +4,12 @@ import "time"
 
 type Handler struct {
 	RequestTimeout time.Duration
+	BatchMaxItems  int
 }
 
 func newHandler() Handler {
 	return Handler{
 		RequestTimeout: getDefaultDurationEnv("HANDLER_REQUEST_TIMEOUT", 100*time.Millisecond),
+		BatchMaxItems:  getDefaultIntEnv("HANDLER_BATCH_MAX_ITEMS", 5000),
 	}
 }
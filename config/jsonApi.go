@@ -0,0 +1,44 @@
+package config
+
+const (
+	QueryAuth  = "query"
+	HeaderAuth = "header"
+	NoAuth     = "none"
+
+	// QuotePerUSD rates tell how many units of the currency one USD buys,
+	// USDPerQuote rates tell how many USD one unit of the currency buys.
+	QuotePerUSD = "per_usd"
+	USDPerQuote = "usd_per"
+)
+
+// JSONAPI describes a provider answering with JSON. The URLs of CurrenciesAPI are templates where
+// {symbol}, {symbols}, {base}, {start}, {end} and {key} are replaced. The key is sent in the AuthName
+// query parameter or header depending on AuthIn, header values are prefixed with AuthPrefix.
+// Paths are dot separated keys leading to the rate map, or to the value of a single currency
+// when they contain {symbol}. ErrorPath leads to the error message of a failed response.
+type JSONAPI struct {
+	AuthIn         string
+	AuthName       string
+	AuthPrefix     string
+	MultiPath      string
+	OnePath        string
+	TimeSeriesPath string
+	ErrorPath      string
+	Quote          string
+	DateLayout     string
+}
+
+// newJSONAPIWithPrefix reads the JSON provider settings from the variables starting with prefix.
+func newJSONAPIWithPrefix(prefix string) JSONAPI {
+	return JSONAPI{
+		AuthIn:         getDefaultEnv(prefix+"AUTH_IN", QueryAuth),
+		AuthName:       getDefaultEnv(prefix+"AUTH_NAME", "api_key"),
+		AuthPrefix:     getDefaultEnv(prefix+"AUTH_PREFIX", ""),
+		MultiPath:      getDefaultEnv(prefix+"MULTI_PATH", ""),
+		OnePath:        getDefaultEnv(prefix+"ONE_PATH", ""),
+		TimeSeriesPath: getDefaultEnv(prefix+"TIME_SERIES_PATH", ""),
+		ErrorPath:      getDefaultEnv(prefix+"ERROR_PATH", "error"),
+		Quote:          getDefaultEnv(prefix+"QUOTE", QuotePerUSD),
+		DateLayout:     getDefaultEnv(prefix+"DATE_LAYOUT", "2006-01-02"),
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	FastForexProvider = "fastforex"
+	ECBProvider       = "ecb"
+	JSONProvider      = "json"
+	FileProvider      = "file"
+
+	FallbackAggregation  = "fallback"
+	ConsensusAggregation = "consensus"
+)
+
+// Provider is a rates source. Types and Currencies list what is fetched from it,
+// currencies not assigned to any provider are fetched from the default one.
+// Fallback lists in order the providers tried when this one fails, or asked along with it for a consensus.
+// JSON describes the responses of a json kind provider, Dir is the directory watched by a file kind provider
+// and WatchInterval how often it is checked for new, changed and removed files.
+type Provider struct {
+	Name          string
+	Kind          string
+	API           CurrenciesAPI
+	JSON          JSONAPI
+	Dir           string
+	WatchInterval time.Duration
+	Types         []string
+	Currencies    []string
+	Fallback      []string
+}
+
+// Providers lists the rates sources. With fallback aggregation the providers of a chain are asked
+// in order until one supplies the rate, with consensus aggregation all of them are asked and
+// the quotes deviating from the median by more than ConsensusTolerance are dropped.
+// SymbolsRefresh is how often the providers reload the symbols they know the currencies by.
+type Providers struct {
+	Default            string
+	List               []Provider
+	Aggregation        string
+	ConsensusTolerance string
+	Quota              Quota
+	SymbolsRefresh     time.Duration
+}
+
+// Quota tells how to save calls once less than LowWatermarkPercent of a provider budget is left:
+// currencies with a priority below MinPriority are skipped and the others are refreshed
+// at most once per LowRefreshInterval.
+type Quota struct {
+	LowWatermarkPercent int
+	LowRefreshInterval  time.Duration
+	MinPriority         int
+}
+
+func newQuota() Quota {
+	return Quota{
+		LowWatermarkPercent: getDefaultIntEnv("CURRENCIES_QUOTA_LOW_WATERMARK_PERCENT", 20),
+		LowRefreshInterval:  getDefaultDurationEnv("CURRENCIES_QUOTA_LOW_REFRESH_INTERVAL", 15*time.Minute),
+		MinPriority:         getDefaultIntEnv("CURRENCIES_QUOTA_MIN_PRIORITY", 1),
+	}
+}
+
+// newProviders reads the providers listed in CURRENCIES_PROVIDERS from CURRENCIES_PROVIDER_<NAME>_* variables.
+// Without the list the single fastforex provider configured by CURRENCIES_API_* is used.
+func newProviders() Providers {
+	aggregation := getDefaultEnv("CURRENCIES_PROVIDERS_AGGREGATION", FallbackAggregation)
+	tolerance := getDefaultEnv("CURRENCIES_PROVIDERS_CONSENSUS_TOLERANCE", "0.02")
+	symbolsRefresh := getDefaultDurationEnv("CURRENCIES_PROVIDERS_SYMBOLS_REFRESH", 1*time.Minute)
+
+	names := getDefaultListEnv("CURRENCIES_PROVIDERS", nil)
+	if len(names) == 0 {
+		return Providers{
+			Default: FastForexProvider,
+			List: []Provider{{
+				Name: FastForexProvider,
+				Kind: FastForexProvider,
+				API:  newCurrenciesAPI(),
+			}},
+			Aggregation:        aggregation,
+			ConsensusTolerance: tolerance,
+			Quota:              newQuota(),
+			SymbolsRefresh:     symbolsRefresh,
+		}
+	}
+
+	providers := Providers{
+		Default:            getDefaultEnv("CURRENCIES_PROVIDERS_DEFAULT", names[0]),
+		List:               make([]Provider, 0, len(names)),
+		Aggregation:        aggregation,
+		ConsensusTolerance: tolerance,
+		Quota:              newQuota(),
+		SymbolsRefresh:     symbolsRefresh,
+	}
+
+	for _, name := range names {
+		prefix := "CURRENCIES_PROVIDER_" + strings.ToUpper(name) + "_"
+
+		providers.List = append(providers.List, Provider{
+			Name:          name,
+			Kind:          getDefaultEnv(prefix+"KIND", FastForexProvider),
+			API:           newCurrenciesAPIWithPrefix(prefix),
+			JSON:          newJSONAPIWithPrefix(prefix),
+			Dir:           getDefaultEnv(prefix+"DIR", ""),
+			WatchInterval: getDefaultDurationEnv(prefix+"WATCH_INTERVAL", 5*time.Second),
+			Types:         getDefaultListEnv(prefix+"TYPES", nil),
+			Currencies:    getDefaultListEnv(prefix+"CURRENCIES", nil),
+			Fallback:      getDefaultListEnv(prefix+"FALLBACK", nil),
+		})
+	}
+
+	return providers
+}
@@ -6,12 +6,17 @@ import (
 	"time"
 
 	"github.com/alemax1/currencies-api/config"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
 	"github.com/alemax1/currencies-api/pkg/logger"
 )
 
+const maintenanceTimeout = 10 * time.Minute
+
 type CurrencyService interface {
 	UpdateCryptoCurrencies(ctx context.Context) error
 	UpdateFiatCurrencies(ctx context.Context) error
+	MaintainHistory(ctx context.Context, policy domain.RetentionPolicy) error
+	SaveProviderHealth(ctx context.Context) error
 }
 
 type Worker struct {
@@ -36,35 +41,103 @@ func New(
 	}
 }
 
+// Run starts the refresh and the history maintenance loops in the background.
 func (w *Worker) Run() {
-	w.wg.Add(1)
+	w.wg.Add(2)
+	go w.refreshLoop()
+	go w.maintenanceLoop()
+}
+
+func (w *Worker) refreshLoop() {
+	defer w.wg.Done()
+
 	ticker := time.NewTicker(w.Cfg.IterationTimeout)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-w.stopCh:
-			w.Logger.Info().Msg("worker successfully shuted down")
-			w.wg.Done()
 			return
-		default:
-			<-ticker.C
+		case <-ticker.C:
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 
 			if err := w.CurrencyService.UpdateFiatCurrencies(ctx); err != nil {
-				w.Logger.Error().Err(err).Msgf("update fiat currencies")
+				w.logUpdateErr("update fiat currencies", err)
+			} else {
+				w.Logger.Info().Msg("fiat currencies successfully updated")
 			}
-			w.Logger.Info().Msg("fiat currencies successfully updated")
 
 			if err := w.CurrencyService.UpdateCryptoCurrencies(ctx); err != nil {
-				w.Logger.Error().Err(err).Msgf("update fiat currencies")
+				w.logUpdateErr("update crypto currencies", err)
+			} else {
+				w.Logger.Info().Msg("crypto currencies successfully updated")
+			}
+
+			if err := w.CurrencyService.SaveProviderHealth(ctx); err != nil {
+				w.Logger.Error().Err(err).Msgf("save provider health")
 			}
-			w.Logger.Info().Msg("crypto currencies successfully updated")
 
 			cancel()
 		}
 	}
 }
 
+// logUpdateErr logs a failed update by the kind of the provider error. Rejected credentials need
+// someone to act, while quota and rate limits resolve by themselves and the breaker backs off meanwhile.
+func (w *Worker) logUpdateErr(msg string, err error) {
+	kind, _ := domain.ProviderErrorKindOf(err)
+
+	switch kind {
+	case domain.ProviderUnauthorized:
+		w.Logger.Error().Err(err).Msgf("%s: provider rejected credentials, check the API key", msg)
+	case domain.ProviderQuotaExceeded:
+		w.Logger.Warn().Err(err).Msgf("%s: provider quota exceeded", msg)
+	case domain.ProviderRateLimited:
+		w.Logger.Warn().Err(err).Msgf("%s: provider rate limited", msg)
+	default:
+		w.Logger.Error().Err(err).Msgf("%s", msg)
+	}
+}
+
+// maintenanceLoop downsamples and purges rate history. It only works on rows older than
+// the downsample age, so it never races with the refresh loop writing fresh observations.
+func (w *Worker) maintenanceLoop() {
+	defer w.wg.Done()
+
+	policy := RetentionPolicy(w.Cfg)
+
+	ticker := time.NewTicker(w.Cfg.MaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), maintenanceTimeout)
+
+			if err := w.CurrencyService.MaintainHistory(ctx, policy); err != nil {
+				w.Logger.Error().Err(err).Msgf("maintain history")
+			} else {
+				w.Logger.Info().Msg("history successfully maintained")
+			}
+
+			cancel()
+		}
+	}
+}
+
+// RetentionPolicy returns the history retention policy configured for the worker.
+func RetentionPolicy(workerCfg config.CurrenciesWorker) domain.RetentionPolicy {
+	return domain.RetentionPolicy{
+		DownsampleAfter: workerCfg.HistoryDownsampleAfter,
+		RawRetention:    workerCfg.HistoryRawRetention,
+		HourlyRetention: workerCfg.HistoryHourlyRetention,
+	}
+}
+
 func (w *Worker) Stop() {
-	w.stopCh <- struct{}{}
+	close(w.stopCh)
 	w.wg.Wait()
+	w.Logger.Info().Msg("worker successfully shuted down")
 }
@@ -0,0 +1,374 @@
+package ecb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alemax1/currencies-api/config"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/alemax1/currencies-api/pkg/httpretry"
+	"github.com/shopspring/decimal"
+)
+
+// Reference rate files published by the ECB, used when the URLs are not configured.
+const (
+	DailyURL      = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	History90dURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	HistoryURL    = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+)
+
+const (
+	baseCurrency = "EUR"
+	usdCurrency  = "USD"
+
+	cubeDate = "2006-01-02"
+
+	// history90dRange is the range covered by the 90 days history file.
+	history90dRange = 89 * 24 * time.Hour
+
+	// rebaseDigits are kept when converting the EUR based rates to USD based ones.
+	rebaseDigits = 16
+
+	// publishHour is the hour in UTC the ECB publishes the rates of a day at, around 16:00 CET.
+	publishHour = 15
+
+	// historyTTL is how long a downloaded history file is reused, a backfill asks for it once per chunk.
+	historyTTL = 10 * time.Minute
+)
+
+// envelope is the eurofxref document: a cube per day holding a cube per currency
+// with the amount of the currency one EUR is worth.
+type envelope struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Cube    struct {
+		Days []dayCube `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+type dayCube struct {
+	Time  string     `xml:"time,attr"`
+	Rates []rateCube `xml:"Cube"`
+}
+
+type rateCube struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+// lists tells whether the day quotes the currency.
+func (c dayCube) lists(currency string) bool {
+	for _, r := range c.Rates {
+		if r.Currency == currency {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ECB fetches the euro foreign exchange reference rates of the European Central Bank.
+// It needs no key and only knows fiat currencies quoted by the ECB, plus EUR and USD.
+// FetchOneURL and FetchMultiURL default to the daily file, TimeSeriesURL to the 90 days
+// or the full history file depending on the requested range. The values are dated with
+// the time the ECB publishes the rates of their day, not with the midnight starting it.
+type ECB struct {
+	Name             string
+	Client           *httpretry.Client
+	CurrenciesAPICfg config.CurrenciesAPI
+	// calls counts the requests sent, retries included, since the last TakeCalls.
+	calls   *atomic.Int64
+	history *historyCache
+}
+
+// historyCache keeps the last downloaded history file for historyTTL.
+type historyCache struct {
+	mu        sync.Mutex
+	url       string
+	doc       envelope
+	fetchedAt time.Time
+}
+
+func New(
+	name string,
+	currenciesAPICfg config.CurrenciesAPI,
+) *ECB {
+	client := httpretry.New(&http.Client{Timeout: currenciesAPICfg.Timeout}, httpretry.Config{
+		Attempts:  currenciesAPICfg.RetryAttempts,
+		BaseDelay: currenciesAPICfg.RetryBaseDelay,
+		MaxDelay:  currenciesAPICfg.RetryMaxDelay,
+	})
+
+	calls := new(atomic.Int64)
+	client.OnAttempt = func() { calls.Add(1) }
+
+	if currenciesAPICfg.FetchOneURL == "" {
+		currenciesAPICfg.FetchOneURL = DailyURL
+	}
+
+	if currenciesAPICfg.FetchMultiURL == "" {
+		currenciesAPICfg.FetchMultiURL = DailyURL
+	}
+
+	return &ECB{
+		Name:             name,
+		Client:           client,
+		CurrenciesAPICfg: currenciesAPICfg,
+		calls:            calls,
+		history:          new(historyCache),
+	}
+}
+
+func (e ECB) SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	rates, fetchedAt, err := e.fetchLatest(ctx, e.CurrenciesAPICfg.FetchMultiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	currenciesResp := make([]domain.CurrencyWithValue, 0, len(currencies))
+
+	for _, currency := range currencies {
+		value, ok := rates[currency.Name]
+		if !ok {
+			continue
+		}
+
+		currenciesResp = append(currenciesResp, domain.CurrencyWithValue{
+			Name:      currency.Name,
+			Value:     value,
+			Provider:  e.Name,
+			FetchedAt: fetchedAt,
+		})
+	}
+
+	if len(currenciesResp) == 0 {
+		return nil, domain.NewProviderError(e.Name, domain.ProviderUnknownSymbol, 0, "no requested currency in response")
+	}
+
+	return currenciesResp, nil
+}
+
+func (e ECB) SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error) {
+	rates, fetchedAt, err := e.fetchLatest(ctx, e.CurrenciesAPICfg.FetchOneURL)
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	value, ok := rates[currency.Name]
+	if !ok {
+		return domain.CurrencyWithValue{}, domain.NewProviderError(e.Name, domain.ProviderUnknownSymbol, 0, currency.Name)
+	}
+
+	return domain.CurrencyWithValue{
+		Name:      currency.Name,
+		Value:     value,
+		Provider:  e.Name,
+		FetchedAt: fetchedAt,
+	}, nil
+}
+
+// SendTimeSeriesRequest returns the values of the currency for every business day in [from, to],
+// the ECB publishes no rates on weekends and TARGET holidays, so a range without any is empty.
+// The currency is unknown only when no day of the file lists it.
+func (e ECB) SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error) {
+	url := e.CurrenciesAPICfg.TimeSeriesURL
+	if url == "" {
+		url = HistoryURL
+		if time.Since(from) < history90dRange {
+			url = History90dURL
+		}
+	}
+
+	doc, err := e.fetchHistory(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	currenciesResp := make([]domain.CurrencyWithValue, 0)
+	listed := currency.Name == baseCurrency || currency.Name == usdCurrency
+
+	for _, cube := range doc.Cube.Days {
+		day, err := time.Parse(cubeDate, cube.Time)
+		if err != nil {
+			return nil, e.malformedErr("date "+cube.Time, err)
+		}
+
+		if day.Before(from) || day.After(to) {
+			listed = listed || cube.lists(currency.Name)
+			continue
+		}
+
+		rates, err := e.rebase(cube)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := rates[currency.Name]
+		if !ok {
+			continue
+		}
+
+		listed = true
+		currenciesResp = append(currenciesResp, domain.CurrencyWithValue{
+			Name:      currency.Name,
+			Value:     value,
+			Provider:  e.Name,
+			FetchedAt: publishedAt(day),
+		})
+	}
+
+	if !listed && len(doc.Cube.Days) > 0 {
+		return nil, domain.NewProviderError(e.Name, domain.ProviderUnknownSymbol, 0, currency.Name)
+	}
+
+	return currenciesResp, nil
+}
+
+// TakeCalls returns the number of requests sent since the previous call and resets it.
+func (e ECB) TakeCalls() int64 {
+	return e.calls.Swap(0)
+}
+
+// fetchLatest returns the USD based values of the most recent day of the document along with its publication time.
+func (e ECB) fetchLatest(ctx context.Context, url string) (map[string]decimal.Decimal, time.Time, error) {
+	doc, err := e.fetch(ctx, url)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if len(doc.Cube.Days) == 0 {
+		return nil, time.Time{}, e.malformedErr("reference rates", nil)
+	}
+
+	latest := doc.Cube.Days[0]
+	for _, cube := range doc.Cube.Days[1:] {
+		if cube.Time > latest.Time {
+			latest = cube
+		}
+	}
+
+	day, err := time.Parse(cubeDate, latest.Time)
+	if err != nil {
+		return nil, time.Time{}, e.malformedErr("date "+latest.Time, err)
+	}
+
+	rates, err := e.rebase(latest)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return rates, publishedAt(day), nil
+}
+
+// publishedAt returns when the rates of the day are published, never later than now.
+func publishedAt(day time.Time) time.Time {
+	published := day.Add(publishHour * time.Hour)
+	if now := time.Now().UTC(); published.After(now) {
+		return now
+	}
+
+	return published
+}
+
+// fetchHistory returns the history file, downloading it again only once the cached one is older than historyTTL.
+func (e ECB) fetchHistory(ctx context.Context, url string) (envelope, error) {
+	e.history.mu.Lock()
+	defer e.history.mu.Unlock()
+
+	if e.history.url == url && time.Since(e.history.fetchedAt) < historyTTL {
+		return e.history.doc, nil
+	}
+
+	doc, err := e.fetch(ctx, url)
+	if err != nil {
+		return envelope{}, err
+	}
+
+	e.history.url, e.history.doc, e.history.fetchedAt = url, doc, time.Now()
+
+	return doc, nil
+}
+
+// rebase converts the EUR based rates of the day to values per one USD.
+func (e ECB) rebase(cube dayCube) (map[string]decimal.Decimal, error) {
+	eurRates := make(map[string]decimal.Decimal, len(cube.Rates)+1)
+	eurRates[baseCurrency] = decimal.NewFromInt(1)
+
+	for _, r := range cube.Rates {
+		value, err := decimal.NewFromString(r.Rate)
+		if err != nil || !value.IsPositive() {
+			return nil, e.malformedErr("rate of "+r.Currency, err)
+		}
+
+		eurRates[r.Currency] = value
+	}
+
+	usdRate, ok := eurRates[usdCurrency]
+	if !ok {
+		return nil, e.malformedErr("reference rates without "+usdCurrency, nil)
+	}
+
+	rates := make(map[string]decimal.Decimal, len(eurRates))
+	for name, value := range eurRates {
+		rates[name] = value.DivRound(usdRate, rebaseDigits)
+	}
+	rates[usdCurrency] = decimal.NewFromInt(1)
+
+	return rates, nil
+}
+
+func (e ECB) fetch(ctx context.Context, url string) (envelope, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return envelope{}, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return envelope{}, fmt.Errorf("client do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return envelope{}, domain.NewProviderError(e.Name, errorKind(resp.StatusCode), resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return envelope{}, fmt.Errorf("read body: %w", err)
+	}
+
+	var doc envelope
+
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return envelope{}, e.malformedErr("reference rates", err)
+	}
+
+	return doc, nil
+}
+
+func (e ECB) malformedErr(what string, err error) error {
+	message := "unexpected " + what
+	if err != nil {
+		message += ": " + err.Error()
+	}
+
+	return domain.NewProviderError(e.Name, domain.ProviderMalformedPayload, http.StatusOK, message)
+}
+
+// errorKind classifies a failed response, the ECB serves static files without error bodies.
+func errorKind(status int) domain.ProviderErrorKind {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return domain.ProviderUnauthorized
+	case http.StatusTooManyRequests:
+		return domain.ProviderRateLimited
+	default:
+		return domain.ProviderUpstream
+	}
+}
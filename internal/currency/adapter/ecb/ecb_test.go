@@ -0,0 +1,188 @@
+package ecb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alemax1/currencies-api/config"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/shopspring/decimal"
+)
+
+// newFixtureECB serves the history fixture for every URL and counts the downloads.
+func newFixtureECB(t *testing.T) (*ECB, *atomic.Int64) {
+	t.Helper()
+
+	downloads := new(atomic.Int64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		http.ServeFile(w, r, "testdata/eurofxref-hist.xml")
+	}))
+	t.Cleanup(server.Close)
+
+	return New("ecb", config.CurrenciesAPI{
+		FetchOneURL:   server.URL,
+		FetchMultiURL: server.URL,
+		TimeSeriesURL: server.URL,
+		Timeout:       time.Second,
+		RetryAttempts: 1,
+	}), downloads
+}
+
+func date(day int) time.Time {
+	return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+// published is when the ECB publishes the rates of the day.
+func published(day int) time.Time {
+	return date(day).Add(publishHour * time.Hour)
+}
+
+func TestSendFetchOneRequest(t *testing.T) {
+	api, _ := newFixtureECB(t)
+
+	got, err := api.SendFetchOneRequest(context.Background(), domain.Currency{Name: "GBP"})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	// One EUR is worth 1.25 USD and 0.8 GBP on the latest day.
+	if !got.Value.Equal(decimal.RequireFromString("0.64")) {
+		t.Errorf("got value %s, want 0.64", got.Value)
+	}
+
+	if !got.FetchedAt.Equal(published(8)) {
+		t.Errorf("got fetched at %s, want the latest publication", got.FetchedAt)
+	}
+
+	_, err = api.SendFetchOneRequest(context.Background(), domain.Currency{Name: "RUB"})
+	if kind, _ := domain.ProviderErrorKindOf(err); kind != domain.ProviderUnknownSymbol {
+		t.Errorf("got error %v for a currency missing on the latest day, want unknown symbol", err)
+	}
+}
+
+func TestSendMultiFetchRequest(t *testing.T) {
+	api, _ := newFixtureECB(t)
+
+	got, err := api.SendMultiFetchRequest(context.Background(), []domain.Currency{{Name: "EUR"}, {Name: "GBP"}, {Name: "XYZ"}})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	want := map[string]string{"EUR": "0.8", "GBP": "0.64"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+
+	for _, value := range got {
+		if !value.Value.Equal(decimal.RequireFromString(want[value.Name])) {
+			t.Errorf("got %s %s, want %s", value.Name, value.Value, want[value.Name])
+		}
+
+		if !value.FetchedAt.Equal(published(8)) {
+			t.Errorf("got %s fetched at %s, want the latest publication", value.Name, value.FetchedAt)
+		}
+	}
+}
+
+func TestSendTimeSeriesRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		from, to time.Time
+		want     map[time.Time]string
+		wantKind domain.ProviderErrorKind
+	}{
+		{
+			name:     "business days in range",
+			currency: "GBP",
+			from:     date(4),
+			to:       date(7),
+			want:     map[time.Time]string{published(4): "0.5", published(5): "0.75"},
+		},
+		{
+			name:     "weekend only",
+			currency: "GBP",
+			from:     date(6),
+			to:       date(7),
+			want:     map[time.Time]string{},
+		},
+		{
+			name:     "listed outside the range",
+			currency: "RUB",
+			from:     date(8),
+			to:       date(8),
+			want:     map[time.Time]string{},
+		},
+		{
+			name:     "never listed",
+			currency: "XYZ",
+			from:     date(4),
+			to:       date(8),
+			wantKind: domain.ProviderUnknownSymbol,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, _ := newFixtureECB(t)
+
+			got, err := api.SendTimeSeriesRequest(context.Background(), domain.Currency{Name: tt.currency}, tt.from, tt.to)
+			if tt.wantKind != "" {
+				if kind, _ := domain.ProviderErrorKindOf(err); kind != tt.wantKind {
+					t.Fatalf("got error %v, want %s", err, tt.wantKind)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+
+			if got == nil || len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %d values", got, len(tt.want))
+			}
+
+			for _, value := range got {
+				if want, ok := tt.want[value.FetchedAt]; !ok || !value.Value.Equal(decimal.RequireFromString(want)) {
+					t.Errorf("got %s on %s, want %s", value.Value, value.FetchedAt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSendTimeSeriesRequestReusesHistory(t *testing.T) {
+	api, downloads := newFixtureECB(t)
+
+	for _, day := range []int{4, 5, 8} {
+		if _, err := api.SendTimeSeriesRequest(context.Background(), domain.Currency{Name: "GBP"}, date(day), date(day)); err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+	}
+
+	if got := downloads.Load(); got != 1 {
+		t.Errorf("got %d downloads, want 1", got)
+	}
+
+	if got := api.TakeCalls(); got != 1 {
+		t.Errorf("got %d calls, want 1", got)
+	}
+}
+
+func TestPublishedAt(t *testing.T) {
+	if got := publishedAt(date(8)); !got.Equal(published(8)) {
+		t.Errorf("got %s, want %s", got, published(8))
+	}
+
+	before := time.Now().UTC()
+	tomorrow := before.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if got := publishedAt(tomorrow); got.Before(before) || got.After(time.Now().UTC()) {
+		t.Errorf("got %s for a day not published yet, want now", got)
+	}
+}
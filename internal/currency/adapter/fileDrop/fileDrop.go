@@ -0,0 +1,406 @@
+package filedrop
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/alemax1/currencies-api/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	csvExt  = ".csv"
+	jsonExt = ".json"
+
+	csvHeader = "currency"
+)
+
+// Dir reads rates from the CSV and JSON files dropped in a directory, for deployments without
+// access to the rates APIs. A CSV file holds "currency,value_usd" rows with an optional header,
+// a JSON file an object of values keyed by currency, values being units of the currency per USD.
+// The modification time of a file is the observed time of its rates and the newest file listing
+// a currency wins. Hidden files are ignored so a file can be written under a hidden name and
+// renamed once complete. Name is recorded as the provider of the read values.
+// The directory is watched by polling it every interval: new and changed files are parsed and
+// removed ones forgotten, so requests only read the parsed rates. A malformed file is logged and
+// skipped, a file that was valid before keeps its last good rates until it is fixed or removed.
+type Dir struct {
+	Name   string
+	Path   string
+	Logger logger.Logger
+
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu sync.RWMutex
+	// files holds the last good parse of every file by path, sorted holds them by modification time.
+	files  map[string]rateFile
+	sorted []rateFile
+	// versions holds the size and modification time of every file by path when it was last parsed,
+	// a file is parsed again once they change.
+	versions map[string]fileVersion
+	// err is the error of the last listing of the directory.
+	err error
+}
+
+type rateFile struct {
+	path    string
+	modTime time.Time
+	rates   map[string]decimal.Decimal
+}
+
+type fileVersion struct {
+	size    int64
+	modTime time.Time
+}
+
+type fileValue struct {
+	value   decimal.Decimal
+	modTime time.Time
+}
+
+// New reads the directory and watches it until Close is called.
+func New(name, path string, interval time.Duration, logger logger.Logger) *Dir {
+	d := &Dir{
+		Name:     name,
+		Path:     path,
+		Logger:   logger,
+		interval: interval,
+		stop:     make(chan struct{}),
+		files:    make(map[string]rateFile),
+		versions: make(map[string]fileVersion),
+	}
+
+	d.scan()
+
+	go d.watch()
+
+	return d
+}
+
+// Close stops watching the directory.
+func (d *Dir) Close() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+}
+
+func (d *Dir) SendMultiFetchRequest(_ context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	files, err := d.rateFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	currenciesResp := make([]domain.CurrencyWithValue, 0, len(currencies))
+
+	for _, currency := range currencies {
+		value, ok := latest(files, currency.Name)
+		if !ok {
+			continue
+		}
+
+		currenciesResp = append(currenciesResp, d.currencyWithValue(currency.Name, value))
+	}
+
+	if len(currenciesResp) == 0 {
+		return nil, domain.NewProviderError(d.Name, domain.ProviderUnknownSymbol, 0, "no requested currency in rate files")
+	}
+
+	return currenciesResp, nil
+}
+
+func (d *Dir) SendFetchOneRequest(_ context.Context, currency domain.Currency) (domain.CurrencyWithValue, error) {
+	files, err := d.rateFiles()
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	value, ok := latest(files, currency.Name)
+	if !ok {
+		return domain.CurrencyWithValue{}, domain.NewProviderError(d.Name, domain.ProviderUnknownSymbol, 0, currency.Name)
+	}
+
+	return d.currencyWithValue(currency.Name, value), nil
+}
+
+// SendTimeSeriesRequest returns the values of the currency from every file modified in [from, to].
+func (d *Dir) SendTimeSeriesRequest(_ context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error) {
+	files, err := d.rateFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		currenciesResp []domain.CurrencyWithValue
+		listed         bool
+	)
+
+	for _, file := range files {
+		value, ok := file.rates[currency.Name]
+		if !ok {
+			continue
+		}
+
+		listed = true
+
+		if file.modTime.Before(from) || file.modTime.After(to) {
+			continue
+		}
+
+		currenciesResp = append(currenciesResp, d.currencyWithValue(currency.Name, fileValue{value: value, modTime: file.modTime}))
+	}
+
+	if !listed {
+		return nil, domain.NewProviderError(d.Name, domain.ProviderUnknownSymbol, 0, currency.Name)
+	}
+
+	return currenciesResp, nil
+}
+
+func (d *Dir) currencyWithValue(name string, value fileValue) domain.CurrencyWithValue {
+	return domain.CurrencyWithValue{
+		Name:      name,
+		Value:     value.value,
+		Provider:  d.Name,
+		FetchedAt: value.modTime,
+	}
+}
+
+// latest returns the value of the currency from the newest file listing it, files are sorted by modification time.
+func latest(files []rateFile, name string) (fileValue, bool) {
+	for i := len(files) - 1; i >= 0; i-- {
+		if value, ok := files[i].rates[name]; ok {
+			return fileValue{value: value, modTime: files[i].modTime}, true
+		}
+	}
+
+	return fileValue{}, false
+}
+
+// rateFiles returns the parsed files sorted by modification time.
+func (d *Dir) rateFiles() ([]rateFile, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if len(d.sorted) == 0 {
+		return nil, domain.NewProviderError(d.Name, domain.ProviderUpstream, 0, "no rate files in "+d.Path)
+	}
+
+	return d.sorted, nil
+}
+
+func (d *Dir) watch() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			d.scan()
+		}
+	}
+}
+
+// scan lists the directory, parses the new and changed files and forgets the removed ones.
+func (d *Dir) scan() {
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		d.Logger.Error().Err(err).Msgf("read rate files dir, provider:%s, dir:%s", d.Name, d.Path)
+
+		d.mu.Lock()
+		d.err = domain.NewProviderError(d.Name, domain.ProviderUpstream, 0, "read dir: "+err.Error())
+		d.mu.Unlock()
+
+		return
+	}
+
+	d.mu.RLock()
+	files := make(map[string]rateFile, len(entries))
+	versions := make(map[string]fileVersion, len(entries))
+	for path, file := range d.files {
+		files[path] = file
+	}
+	for path, version := range d.versions {
+		versions[path] = version
+	}
+	d.mu.RUnlock()
+
+	seen := make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || (ext != csvExt && ext != jsonExt) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				d.Logger.Error().Err(err).Msgf("stat rate file, provider:%s, file:%s", d.Name, entry.Name())
+			}
+
+			continue
+		}
+
+		path := filepath.Join(d.Path, entry.Name())
+		seen[path] = struct{}{}
+
+		version := fileVersion{size: info.Size(), modTime: info.ModTime().UTC()}
+		if last, ok := versions[path]; ok && last == version {
+			continue
+		}
+		versions[path] = version
+
+		rates, err := d.parse(path, ext)
+		if err != nil {
+			d.Logger.Error().Err(err).Msgf("skip rate file, provider:%s, file:%s", d.Name, entry.Name())
+			continue
+		}
+
+		files[path] = rateFile{
+			path:    path,
+			modTime: version.modTime,
+			rates:   rates,
+		}
+	}
+
+	for path := range versions {
+		if _, ok := seen[path]; !ok {
+			delete(versions, path)
+			delete(files, path)
+		}
+	}
+
+	sorted := make([]rateFile, 0, len(files))
+	for _, file := range files {
+		sorted = append(sorted, file)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].modTime.Equal(sorted[j].modTime) {
+			return sorted[i].modTime.Before(sorted[j].modTime)
+		}
+
+		return sorted[i].path < sorted[j].path
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.files, d.versions, d.sorted, d.err = files, versions, sorted, nil
+}
+
+func (d *Dir) parse(path, ext string) (map[string]decimal.Decimal, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+
+	var rows [][2]string
+
+	if ext == csvExt {
+		rows, err = readCSV(f)
+	} else {
+		rows, err = readJSON(f)
+	}
+
+	if err != nil {
+		return nil, d.malformedErr(path, err)
+	}
+
+	rates := make(map[string]decimal.Decimal, len(rows))
+
+	for _, row := range rows {
+		name := strings.ToUpper(strings.TrimSpace(row[0]))
+		if len(name) < 2 || len(name) > 255 {
+			return nil, d.malformedErr(path, fmt.Errorf("invalid currency %q", row[0]))
+		}
+
+		if _, ok := rates[name]; ok {
+			return nil, d.malformedErr(path, fmt.Errorf("duplicate currency %s", name))
+		}
+
+		value, err := decimal.NewFromString(strings.TrimSpace(row[1]))
+		if err != nil || !value.IsPositive() {
+			return nil, d.malformedErr(path, fmt.Errorf("invalid value of %s: %q", name, row[1]))
+		}
+
+		rates[name] = value
+	}
+
+	if len(rates) == 0 {
+		return nil, d.malformedErr(path, errors.New("no rates"))
+	}
+
+	return rates, nil
+}
+
+func (d *Dir) malformedErr(path string, err error) error {
+	return domain.NewProviderError(d.Name, domain.ProviderMalformedPayload, 0, filepath.Base(path)+": "+err.Error())
+}
+
+func readCSV(r io.Reader) ([][2]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && strings.EqualFold(strings.TrimSpace(records[0][0]), csvHeader) {
+		records = records[1:]
+	}
+
+	rows := make([][2]string, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, [2]string{record[0], record[1]})
+	}
+
+	return rows, nil
+}
+
+func readJSON(r io.Reader) ([][2]string, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	rows := make([][2]string, 0, len(values))
+	for name, value := range values {
+		switch number := value.(type) {
+		case json.Number:
+			rows = append(rows, [2]string{name, number.String()})
+		case string:
+			rows = append(rows, [2]string{name, number})
+		default:
+			return nil, fmt.Errorf("invalid value of %s", name)
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,339 @@
+package filedrop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var modTime = time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+
+// newDir returns a provider over an empty directory that is only rescanned by the test.
+func newDir(t *testing.T) *Dir {
+	t.Helper()
+
+	nop := zerolog.Nop()
+	d := New("drop", t.TempDir(), time.Hour, &nop)
+	t.Cleanup(d.Close)
+
+	return d
+}
+
+// drop writes the file into the directory with the modification time.
+func drop(t *testing.T, d *Dir, name, content string, at time.Time) {
+	t.Helper()
+
+	path := filepath.Join(d.Path, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatalf("chtimes %s: %v", name, err)
+	}
+}
+
+func kindOf(err error) domain.ProviderErrorKind {
+	kind, _ := domain.ProviderErrorKindOf(err)
+
+	return kind
+}
+
+func fetchOne(t *testing.T, d *Dir, name string) (domain.CurrencyWithValue, error) {
+	t.Helper()
+
+	return d.SendFetchOneRequest(context.Background(), domain.Currency{Name: name})
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    map[string]string
+	}{
+		{
+			name:    "csv with header",
+			file:    "rates.csv",
+			content: "currency,value_usd\nEUR,0.91\ngbp, 0.79\n",
+			want:    map[string]string{"EUR": "0.91", "GBP": "0.79"},
+		},
+		{
+			name:    "csv without header",
+			file:    "rates.csv",
+			content: "# comment\nEUR,0.91\nGBP,0.79\n",
+			want:    map[string]string{"EUR": "0.91", "GBP": "0.79"},
+		},
+		{
+			name:    "json numbers",
+			file:    "rates.json",
+			content: `{"EUR": 0.91, "GBP": 0.790000000000000000001}`,
+			want:    map[string]string{"EUR": "0.91", "GBP": "0.790000000000000000001"},
+		},
+		{
+			name:    "json strings",
+			file:    "rates.JSON",
+			content: `{"eur": "0.91", "GBP": "0.79"}`,
+			want:    map[string]string{"EUR": "0.91", "GBP": "0.79"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDir(t)
+			drop(t, d, tt.file, tt.content, modTime)
+			d.scan()
+
+			for name, want := range tt.want {
+				got, err := fetchOne(t, d, name)
+				if err != nil {
+					t.Fatalf("fetch %s: %v", name, err)
+				}
+
+				if !got.Value.Equal(decimal.RequireFromString(want)) {
+					t.Errorf("got %s %s, want %s", name, got.Value, want)
+				}
+
+				if !got.FetchedAt.Equal(modTime) {
+					t.Errorf("got %s fetched at %s, want the file modification time %s", name, got.FetchedAt, modTime)
+				}
+
+				if got.Provider != "drop" {
+					t.Errorf("got provider %s, want drop", got.Provider)
+				}
+			}
+		})
+	}
+}
+
+func TestIgnoredFiles(t *testing.T) {
+	d := newDir(t)
+	drop(t, d, ".rates.csv", "EUR,0.5\n", modTime.Add(time.Hour))
+	drop(t, d, "rates.csv.part", "EUR,0.6\n", modTime.Add(time.Hour))
+	drop(t, d, "rates.txt", "EUR,0.7\n", modTime.Add(time.Hour))
+	drop(t, d, "rates.csv", "EUR,0.91\n", modTime)
+
+	if err := os.Mkdir(filepath.Join(d.Path, "old.csv"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	d.scan()
+
+	got, err := fetchOne(t, d, "EUR")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	if !got.Value.Equal(decimal.RequireFromString("0.91")) {
+		t.Errorf("got %s, want the value of the only complete file", got.Value)
+	}
+}
+
+func TestNewestFileWins(t *testing.T) {
+	d := newDir(t)
+	drop(t, d, "b.csv", "EUR,0.92\nGBP,0.79\n", modTime)
+	drop(t, d, "a.json", `{"EUR": "0.93"}`, modTime.Add(time.Hour))
+	drop(t, d, "c.csv", "EUR,0.90\n", modTime.Add(-time.Hour))
+	d.scan()
+
+	got, err := d.SendMultiFetchRequest(context.Background(), []domain.Currency{{Name: "EUR"}, {Name: "GBP"}, {Name: "JPY"}})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	want := map[string]domain.CurrencyWithValue{
+		"EUR": {Value: decimal.RequireFromString("0.93"), FetchedAt: modTime.Add(time.Hour)},
+		"GBP": {Value: decimal.RequireFromString("0.79"), FetchedAt: modTime},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+
+	for _, value := range got {
+		w := want[value.Name]
+		if !value.Value.Equal(w.Value) || !value.FetchedAt.Equal(w.FetchedAt) {
+			t.Errorf("got %s %s at %s, want %s at %s", value.Name, value.Value, value.FetchedAt, w.Value, w.FetchedAt)
+		}
+	}
+}
+
+func TestMalformedFiles(t *testing.T) {
+	malformed := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "csv with a missing value", file: "bad.csv", content: "EUR\n"},
+		{name: "csv with a negative value", file: "bad.csv", content: "EUR,-1\n"},
+		{name: "csv with a duplicate currency", file: "bad.csv", content: "EUR,1\neur,2\n"},
+		{name: "csv without rates", file: "bad.csv", content: "currency,value_usd\n"},
+		{name: "invalid json", file: "bad.json", content: `{"EUR": `},
+		{name: "json with an object value", file: "bad.json", content: `{"EUR": {"value": 1}}`},
+		{name: "json with an invalid currency", file: "bad.json", content: `{"E": "1"}`},
+	}
+
+	for _, tt := range malformed {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDir(t)
+			drop(t, d, "good.csv", "EUR,0.91\n", modTime)
+			drop(t, d, tt.file, tt.content, modTime.Add(time.Hour))
+			d.scan()
+
+			got, err := fetchOne(t, d, "EUR")
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+
+			if !got.Value.Equal(decimal.RequireFromString("0.91")) {
+				t.Errorf("got %s, want the value of the valid file", got.Value)
+			}
+		})
+	}
+
+	t.Run("changed file keeps its last good rates", func(t *testing.T) {
+		d := newDir(t)
+		drop(t, d, "rates.csv", "EUR,0.91\n", modTime)
+		d.scan()
+
+		drop(t, d, "rates.csv", "EUR,broken\n", modTime.Add(time.Hour))
+		d.scan()
+
+		got, err := fetchOne(t, d, "EUR")
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+
+		if !got.Value.Equal(decimal.RequireFromString("0.91")) || !got.FetchedAt.Equal(modTime) {
+			t.Errorf("got %s at %s, want the last good parse", got.Value, got.FetchedAt)
+		}
+
+		drop(t, d, "rates.csv", "EUR,0.95\n", modTime.Add(2*time.Hour))
+		d.scan()
+
+		if got, err = fetchOne(t, d, "EUR"); err != nil || !got.Value.Equal(decimal.RequireFromString("0.95")) {
+			t.Errorf("got %s, %v, want the fixed file", got.Value, err)
+		}
+	})
+
+	t.Run("only malformed files", func(t *testing.T) {
+		d := newDir(t)
+		drop(t, d, "bad.csv", "EUR\n", modTime)
+		d.scan()
+
+		if _, err := fetchOne(t, d, "EUR"); kindOf(err) != domain.ProviderUpstream {
+			t.Errorf("got %v, want an upstream error", err)
+		}
+	})
+}
+
+func TestUnknownCurrency(t *testing.T) {
+	d := newDir(t)
+	drop(t, d, "rates.csv", "EUR,0.91\n", modTime)
+	d.scan()
+
+	if _, err := fetchOne(t, d, "JPY"); kindOf(err) != domain.ProviderUnknownSymbol {
+		t.Errorf("got %v, want an unknown symbol error", err)
+	}
+
+	_, err := d.SendMultiFetchRequest(context.Background(), []domain.Currency{{Name: "JPY"}})
+	if kindOf(err) != domain.ProviderUnknownSymbol {
+		t.Errorf("got %v, want an unknown symbol error", err)
+	}
+}
+
+func TestRemovedFile(t *testing.T) {
+	d := newDir(t)
+	drop(t, d, "old.csv", "EUR,0.91\n", modTime)
+	drop(t, d, "new.csv", "EUR,0.93\n", modTime.Add(time.Hour))
+	d.scan()
+
+	if err := os.Remove(filepath.Join(d.Path, "new.csv")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	d.scan()
+
+	got, err := fetchOne(t, d, "EUR")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	if !got.Value.Equal(decimal.RequireFromString("0.91")) {
+		t.Errorf("got %s, want the value of the remaining file", got.Value)
+	}
+}
+
+func TestMissingDir(t *testing.T) {
+	nop := zerolog.Nop()
+	d := New("drop", filepath.Join(t.TempDir(), "missing"), time.Hour, &nop)
+	t.Cleanup(d.Close)
+
+	if _, err := fetchOne(t, d, "EUR"); kindOf(err) != domain.ProviderUpstream {
+		t.Errorf("got %v, want an upstream error", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	nop := zerolog.Nop()
+	d := New("drop", t.TempDir(), 10*time.Millisecond, &nop)
+	t.Cleanup(d.Close)
+
+	if _, err := fetchOne(t, d, "EUR"); err == nil {
+		t.Fatal("got a value from an empty directory")
+	}
+
+	drop(t, d, "rates.csv", "EUR,0.91\n", modTime)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, err := fetchOne(t, d, "EUR")
+		if err == nil {
+			if !got.Value.Equal(decimal.RequireFromString("0.91")) {
+				t.Errorf("got %s, want the dropped value", got.Value)
+			}
+
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped file not picked up: %v", err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSendTimeSeriesRequest(t *testing.T) {
+	d := newDir(t)
+	drop(t, d, "a.csv", "EUR,0.90\n", modTime)
+	drop(t, d, "b.csv", "EUR,0.91\nGBP,0.79\n", modTime.Add(time.Hour))
+	drop(t, d, "c.csv", "EUR,0.92\n", modTime.Add(2*time.Hour))
+	d.scan()
+
+	got, err := d.SendTimeSeriesRequest(context.Background(), domain.Currency{Name: "EUR"}, modTime.Add(time.Hour), modTime.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	want := []string{"0.91", "0.92"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+
+	for i, value := range got {
+		if !value.Value.Equal(decimal.RequireFromString(want[i])) {
+			t.Errorf("got %s at %d, want %s", value.Value, i, want[i])
+		}
+	}
+
+	got, err = d.SendTimeSeriesRequest(context.Background(), domain.Currency{Name: "GBP"}, modTime.Add(2*time.Hour), modTime.Add(3*time.Hour))
+	if err != nil || len(got) != 0 {
+		t.Errorf("got %v, %v, want no values of a currency listed outside the range", got, err)
+	}
+}
@@ -0,0 +1,182 @@
+package forex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// API is the set of provider calls guarded by the breaker.
+type API interface {
+	SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error)
+	SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error)
+	SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error)
+}
+
+// latencySmoothing is the weight of the previous average in the latency moving average.
+const latencySmoothing = 4
+
+// Breaker stops calling a provider after failureThreshold consecutive failures. Once openTimeout
+// passes a single probe request is let through, closing the breaker on success and reopening it on failure.
+type Breaker struct {
+	wrapped
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu     sync.Mutex
+	health domain.ProviderHealth
+	// probing is set while the half-open probe request is in flight.
+	probing bool
+}
+
+func NewBreaker(
+	name string,
+	api API,
+	failureThreshold int,
+	openTimeout time.Duration,
+) *Breaker {
+	return &Breaker{
+		wrapped:          wrapped{API: api, name: name},
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		health: domain.ProviderHealth{
+			Provider:  name,
+			State:     domain.BreakerClosed,
+			UpdatedAt: time.Now().UTC(),
+		},
+	}
+}
+
+func (b *Breaker) SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error) {
+	if err := b.allow(); err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	start := time.Now()
+	resp, err := b.API.SendFetchOneRequest(ctx, currency)
+	b.record(time.Since(start), err)
+
+	return resp, err
+}
+
+func (b *Breaker) SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := b.API.SendMultiFetchRequest(ctx, currencies)
+	b.record(time.Since(start), err)
+
+	return resp, err
+}
+
+func (b *Breaker) SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := b.API.SendTimeSeriesRequest(ctx, currency, from, to)
+	b.record(time.Since(start), err)
+
+	return resp, err
+}
+
+// Health returns the current state of the breaker.
+func (b *Breaker) Health() domain.ProviderHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.health
+}
+
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.health.State {
+	case domain.BreakerOpen:
+		if time.Since(b.health.OpenedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+
+		b.setState(domain.BreakerHalfOpen)
+		b.probing = true
+
+		return nil
+	case domain.BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+
+		b.probing = true
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *Breaker) record(latency time.Duration, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A request canceled by the caller says nothing about the provider, only the probe slot is released.
+	if errors.Is(err, context.Canceled) {
+		b.probing = false
+
+		return
+	}
+
+	now := time.Now().UTC()
+
+	b.health.LastLatency = latency
+	if b.health.AvgLatency == 0 {
+		b.health.AvgLatency = latency
+	} else {
+		b.health.AvgLatency = (b.health.AvgLatency*latencySmoothing + latency) / (latencySmoothing + 1)
+	}
+	b.health.UpdatedAt = now
+
+	if !isProviderFailure(err) {
+		b.probing = false
+		b.health.ConsecutiveFailures = 0
+		b.health.LastSuccessAt = now
+		b.setState(domain.BreakerClosed)
+
+		return
+	}
+
+	b.health.ConsecutiveFailures++
+	b.health.LastError = err.Error()
+	b.health.LastFailureAt = now
+
+	if b.health.State == domain.BreakerHalfOpen || b.health.ConsecutiveFailures >= b.failureThreshold {
+		b.probing = false
+		b.health.OpenedAt = now
+		b.setState(domain.BreakerOpen)
+	}
+}
+
+func (b *Breaker) setState(state domain.BreakerState) {
+	b.health.State = state
+	b.health.UpdatedAt = time.Now().UTC()
+}
+
+// isProviderFailure tells whether the error means the provider is unhealthy.
+// A currency unknown to the provider is not a failure of the provider.
+func isProviderFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	kind, _ := domain.ProviderErrorKindOf(err)
+
+	return kind != domain.ProviderUnknownSymbol
+}
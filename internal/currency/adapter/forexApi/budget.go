@@ -0,0 +1,102 @@
+package forex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// Budget stops calling a provider once the calls left in its budget are used up, so an update
+// asking for many currencies cannot overrun the budget between two recordings of the usage.
+// The calls of a request, retries included, are counted once it returns: a request started with
+// one call left may still use a few more. Without a limit set every call is let through.
+type Budget struct {
+	wrapped
+
+	mu        sync.Mutex
+	limited   bool
+	remaining int64
+	// calls counts the requests sent since the last TakeCalls.
+	calls int64
+}
+
+func NewBudget(name string, api API) *Budget {
+	return &Budget{
+		wrapped: wrapped{API: api, name: name},
+	}
+}
+
+// Limit sets the calls left in the budget, an unlimited provider is never stopped.
+func (b *Budget) Limit(remaining int64, limited bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remaining, b.limited = remaining, limited
+}
+
+func (b *Budget) SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error) {
+	if err := b.allow(); err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	defer b.count()
+
+	return b.API.SendFetchOneRequest(ctx, currency)
+}
+
+func (b *Budget) SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	defer b.count()
+
+	return b.API.SendMultiFetchRequest(ctx, currencies)
+}
+
+func (b *Budget) SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	defer b.count()
+
+	return b.API.SendTimeSeriesRequest(ctx, currency, from, to)
+}
+
+// TakeCalls returns the number of requests sent since the previous call and resets it.
+func (b *Budget) TakeCalls() int64 {
+	b.count()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	calls := b.calls
+	b.calls = 0
+
+	return calls
+}
+
+func (b *Budget) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limited && b.remaining <= 0 {
+		return domain.NewProviderError(b.name, domain.ProviderQuotaExceeded, 0, "call budget exhausted")
+	}
+
+	return nil
+}
+
+// count moves the calls made by the wrapped provider to the budget.
+func (b *Budget) count() {
+	calls := b.wrapped.TakeCalls()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.calls += calls
+	b.remaining -= calls
+}
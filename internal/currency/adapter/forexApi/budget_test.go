@@ -0,0 +1,90 @@
+package forex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// countingAPI makes callsPerRequest calls, as retries do, on every request.
+type countingAPI struct {
+	callsPerRequest int64
+	requests        int
+	calls           int64
+}
+
+func (a *countingAPI) SendFetchOneRequest(context.Context, domain.Currency) (domain.CurrencyWithValue, error) {
+	a.requests++
+	a.calls += a.callsPerRequest
+
+	return domain.CurrencyWithValue{}, nil
+}
+
+func (a *countingAPI) SendMultiFetchRequest(context.Context, []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	a.requests++
+	a.calls += a.callsPerRequest
+
+	return nil, nil
+}
+
+func (a *countingAPI) SendTimeSeriesRequest(context.Context, domain.Currency, time.Time, time.Time) ([]domain.CurrencyWithValue, error) {
+	a.requests++
+	a.calls += a.callsPerRequest
+
+	return nil, nil
+}
+
+func (a *countingAPI) TakeCalls() int64 {
+	calls := a.calls
+	a.calls = 0
+
+	return calls
+}
+
+func TestBudget(t *testing.T) {
+	tests := []struct {
+		name            string
+		remaining       int64
+		limited         bool
+		callsPerRequest int64
+		wantRequests    int
+		wantCalls       int64
+	}{
+		{name: "unlimited", callsPerRequest: 1, wantRequests: 10, wantCalls: 10},
+		{name: "stops at zero", remaining: 3, limited: true, callsPerRequest: 1, wantRequests: 3, wantCalls: 3},
+		{name: "retries count", remaining: 3, limited: true, callsPerRequest: 2, wantRequests: 2, wantCalls: 4},
+		{name: "exhausted", remaining: 0, limited: true, callsPerRequest: 1, wantRequests: 0, wantCalls: 0},
+		{name: "overrun", remaining: -2, limited: true, callsPerRequest: 1, wantRequests: 0, wantCalls: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &countingAPI{callsPerRequest: tt.callsPerRequest}
+			budget := NewBudget("test", api)
+			budget.Limit(tt.remaining, tt.limited)
+
+			var exhausted int
+
+			for i := 0; i < 10; i++ {
+				_, err := budget.SendFetchOneRequest(context.Background(), domain.Currency{Name: "EUR"})
+				if kind, _ := domain.ProviderErrorKindOf(err); kind == domain.ProviderQuotaExceeded {
+					exhausted++
+				}
+			}
+
+			if api.requests != tt.wantRequests || exhausted != 10-tt.wantRequests {
+				t.Errorf("got %d requests and %d refusals, want %d requests", api.requests, exhausted, tt.wantRequests)
+			}
+
+			if calls := budget.TakeCalls(); calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+
+			if calls := budget.TakeCalls(); calls != 0 {
+				t.Errorf("got %d calls after taking them, want 0", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package forex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// SymbolRepo returns the symbols a provider knows the currencies by.
+type SymbolRepo interface {
+	GetProviderSymbols(ctx context.Context, provider string) ([]domain.ProviderSymbol, error)
+}
+
+// Symbols asks the provider for the currencies by their symbols, as WETH priced as ETH or
+// a lowercase id, and names the values of the response back after the currencies.
+// The mapping is reloaded at most once per refresh, the last loaded one is kept while reloading fails.
+type Symbols struct {
+	wrapped
+	repo    SymbolRepo
+	refresh time.Duration
+
+	mu       sync.Mutex
+	toSymbol map[string]string
+	loadedAt time.Time
+}
+
+func NewSymbols(
+	name string,
+	api API,
+	repo SymbolRepo,
+	refresh time.Duration,
+) *Symbols {
+	return &Symbols{
+		wrapped: wrapped{API: api, name: name},
+		repo:    repo,
+		refresh: refresh,
+	}
+}
+
+func (s *Symbols) SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error) {
+	toSymbol, err := s.mapping(ctx)
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	symbol, ok := toSymbol[currency.Name]
+	if !ok {
+		return s.API.SendFetchOneRequest(ctx, currency)
+	}
+
+	mapped := currency
+	mapped.Name = symbol
+
+	resp, err := s.API.SendFetchOneRequest(ctx, mapped)
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	resp.Name = currency.Name
+
+	return resp, nil
+}
+
+// SendMultiFetchRequest asks once for a symbol shared by several currencies, as WETH mapped
+// to ETH along with ETH itself, and gives its value to each of them.
+func (s *Symbols) SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	toSymbol, err := s.mapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(toSymbol) == 0 {
+		return s.API.SendMultiFetchRequest(ctx, currencies)
+	}
+
+	names := make(map[string][]string, len(currencies))
+	mapped := make([]domain.Currency, 0, len(currencies))
+
+	for _, currency := range currencies {
+		symbol, ok := toSymbol[currency.Name]
+		if !ok {
+			symbol = currency.Name
+		}
+
+		if _, ok := names[symbol]; !ok {
+			request := currency
+			request.Name = symbol
+			mapped = append(mapped, request)
+		}
+
+		names[symbol] = append(names[symbol], currency.Name)
+	}
+
+	resp, err := s.API.SendMultiFetchRequest(ctx, mapped)
+	if err != nil {
+		return nil, err
+	}
+
+	currenciesResp := make([]domain.CurrencyWithValue, 0, len(currencies))
+
+	for _, value := range resp {
+		for _, name := range names[value.Name] {
+			value.Name = name
+			currenciesResp = append(currenciesResp, value)
+		}
+	}
+
+	return currenciesResp, nil
+}
+
+func (s *Symbols) SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error) {
+	toSymbol, err := s.mapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, ok := toSymbol[currency.Name]
+	if !ok {
+		return s.API.SendTimeSeriesRequest(ctx, currency, from, to)
+	}
+
+	mapped := currency
+	mapped.Name = symbol
+
+	resp, err := s.API.SendTimeSeriesRequest(ctx, mapped, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp {
+		resp[i].Name = currency.Name
+	}
+
+	return resp, nil
+}
+
+// mapping returns the symbols of the provider keyed by currency name.
+func (s *Symbols) mapping(ctx context.Context) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.toSymbol != nil && time.Since(s.loadedAt) < s.refresh {
+		return s.toSymbol, nil
+	}
+
+	symbols, err := s.repo.GetProviderSymbols(ctx, s.name)
+	if err != nil {
+		if s.toSymbol != nil {
+			s.loadedAt = time.Now()
+
+			return s.toSymbol, nil
+		}
+
+		return nil, fmt.Errorf("get provider symbols: %w", err)
+	}
+
+	toSymbol := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		toSymbol[symbol.Currency] = symbol.Symbol
+	}
+
+	s.toSymbol = toSymbol
+	s.loadedAt = time.Now()
+
+	return toSymbol, nil
+}
@@ -0,0 +1,36 @@
+package forex
+
+import (
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// wrapped is the provider a decorator calls. It forwards the health and the call count
+// to the provider, so a decorator only defines the ones it changes.
+type wrapped struct {
+	API
+	name string
+}
+
+// Health forwards to the provider, a provider without breaker is reported closed.
+func (w wrapped) Health() domain.ProviderHealth {
+	if reporter, ok := w.API.(interface{ Health() domain.ProviderHealth }); ok {
+		return reporter.Health()
+	}
+
+	return domain.ProviderHealth{
+		Provider:  w.name,
+		State:     domain.BreakerClosed,
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+// TakeCalls forwards to the provider when it counts its calls.
+func (w wrapped) TakeCalls() int64 {
+	if counter, ok := w.API.(interface{ TakeCalls() int64 }); ok {
+		return counter.TakeCalls()
+	}
+
+	return 0
+}
@@ -0,0 +1,385 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/alemax1/currencies-api/config"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/alemax1/currencies-api/pkg/httpretry"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	usdCurrency = "USD"
+
+	// invertDigits are kept when turning USD per unit rates into units per USD.
+	invertDigits = 16
+)
+
+// API fetches rates from a provider described by a Spec. Name is recorded as the provider of the fetched values.
+type API struct {
+	Name   string
+	Client *httpretry.Client
+	APIKey string
+	Spec   Spec
+	// calls counts the requests sent, retries included, since the last TakeCalls.
+	calls *atomic.Int64
+}
+
+func New(
+	name string,
+	currenciesAPICfg config.CurrenciesAPI,
+	spec Spec,
+) *API {
+	client := httpretry.New(&http.Client{Timeout: currenciesAPICfg.Timeout}, httpretry.Config{
+		Attempts:  currenciesAPICfg.RetryAttempts,
+		BaseDelay: currenciesAPICfg.RetryBaseDelay,
+		MaxDelay:  currenciesAPICfg.RetryMaxDelay,
+	})
+
+	calls := new(atomic.Int64)
+	client.OnAttempt = func() { calls.Add(1) }
+
+	return &API{
+		Name:   name,
+		Client: client,
+		APIKey: currenciesAPICfg.APIKey,
+		Spec:   spec,
+		calls:  calls,
+	}
+}
+
+func (a API) SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	symbols := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		symbols = append(symbols, currency.Name)
+	}
+
+	doc, err := a.get(ctx, a.Spec.FetchMultiURL, map[string]string{
+		symbolsPlaceholder: strings.Join(symbols, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	currenciesResp := make([]domain.CurrencyWithValue, 0, len(currencies))
+	fetchedAt := time.Now().UTC()
+
+	for _, currency := range currencies {
+		node, ok, err := a.lookup(doc, a.Spec.MultiPath, currency.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		value, err := a.value(node, currency.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		currenciesResp = append(currenciesResp, domain.CurrencyWithValue{
+			Name:      currency.Name,
+			Value:     value,
+			Provider:  a.Name,
+			FetchedAt: fetchedAt,
+		})
+	}
+
+	if len(currenciesResp) == 0 {
+		return nil, domain.NewProviderError(a.Name, domain.ProviderUnknownSymbol, 0, "no requested currency in response")
+	}
+
+	return currenciesResp, nil
+}
+
+func (a API) SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error) {
+	doc, err := a.get(ctx, a.Spec.FetchOneURL, map[string]string{
+		symbolPlaceholder: currency.Name,
+	})
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	node, ok, err := a.lookup(doc, a.Spec.OnePath, currency.Name)
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	if !ok {
+		return domain.CurrencyWithValue{}, domain.NewProviderError(a.Name, domain.ProviderUnknownSymbol, 0, currency.Name)
+	}
+
+	value, err := a.value(node, currency.Name)
+	if err != nil {
+		return domain.CurrencyWithValue{}, err
+	}
+
+	return domain.CurrencyWithValue{
+		Name:      currency.Name,
+		Value:     value,
+		Provider:  a.Name,
+		FetchedAt: time.Now().UTC(),
+	}, nil
+}
+
+// SendTimeSeriesRequest returns the values of the currency for every day in [from, to] listed by the provider.
+func (a API) SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error) {
+	if a.Spec.TimeSeriesURL == "" {
+		return nil, fmt.Errorf("provider %s: time series url is not configured", a.Name)
+	}
+
+	doc, err := a.get(ctx, a.Spec.TimeSeriesURL, map[string]string{
+		symbolPlaceholder: currency.Name,
+		startPlaceholder:  from.Format(a.Spec.DateLayout),
+		endPlaceholder:    to.Format(a.Spec.DateLayout),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	node, ok, err := a.lookup(doc, a.Spec.TimeSeriesPath, currency.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, domain.NewProviderError(a.Name, domain.ProviderUnknownSymbol, 0, currency.Name)
+	}
+
+	series, ok := node.(map[string]any)
+	if !ok {
+		return nil, a.malformedErr("time series of "+currency.Name, nil)
+	}
+
+	currenciesResp := make([]domain.CurrencyWithValue, 0, len(series))
+
+	for date, number := range series {
+		day, err := time.Parse(a.Spec.DateLayout, date)
+		if err != nil {
+			return nil, a.malformedErr("date "+date, err)
+		}
+
+		value, err := a.value(number, date)
+		if err != nil {
+			return nil, err
+		}
+
+		currenciesResp = append(currenciesResp, domain.CurrencyWithValue{
+			Name:      currency.Name,
+			Value:     value,
+			Provider:  a.Name,
+			FetchedAt: day,
+		})
+	}
+
+	return currenciesResp, nil
+}
+
+// TakeCalls returns the number of requests sent since the previous call and resets it.
+func (a API) TakeCalls() int64 {
+	return a.calls.Swap(0)
+}
+
+// get sends a request to the filled URL template and returns the decoded body of a successful response.
+// A failed response, or a successful one carrying an error message, is turned into a provider error
+// of the kind told by its status and error message.
+func (a API) get(ctx context.Context, template string, values map[string]string) (any, error) {
+	values[basePlaceholder] = usdCurrency
+	values[keyPlaceholder] = a.APIKey
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, expand(template, values), nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	switch a.Spec.AuthIn {
+	case config.QueryAuth:
+		query := req.URL.Query()
+		query.Set(a.Spec.AuthName, a.APIKey)
+		req.URL.RawQuery = query.Encode()
+	case config.HeaderAuth:
+		req.Header.Set(a.Spec.AuthName, a.Spec.AuthPrefix+a.APIKey)
+	}
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	doc, decodeErr := decode(body)
+	message := a.errorMessage(doc)
+
+	if resp.StatusCode != http.StatusOK {
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+
+		return nil, domain.NewProviderError(a.Name, errorKind(resp.StatusCode, message), resp.StatusCode, message)
+	}
+
+	if decodeErr != nil {
+		return nil, a.malformedErr("response", decodeErr)
+	}
+
+	if message != "" {
+		return nil, domain.NewProviderError(a.Name, errorKind(resp.StatusCode, message), resp.StatusCode, message)
+	}
+
+	return doc, nil
+}
+
+// lookup walks the path down the document. A path without {symbol} leads to the rate map,
+// so the symbol is looked up in it. A key missing once the symbol is reached means the
+// currency is not listed, a key missing before means the response is malformed.
+func (a API) lookup(doc any, path, symbol string) (any, bool, error) {
+	keys := strings.FieldsFunc(path, func(r rune) bool { return r == '.' })
+	if !strings.Contains(path, symbolPlaceholder) {
+		keys = append(keys, symbolPlaceholder)
+	}
+
+	var symbolReached bool
+
+	node := doc
+	for _, key := range keys {
+		if strings.Contains(key, symbolPlaceholder) {
+			key = strings.ReplaceAll(key, symbolPlaceholder, symbol)
+			symbolReached = true
+		}
+
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, false, a.malformedErr(path, nil)
+		}
+
+		node, ok = object[key]
+		if !ok {
+			if symbolReached {
+				return nil, false, nil
+			}
+
+			return nil, false, a.malformedErr(path, nil)
+		}
+	}
+
+	return node, true, nil
+}
+
+// value reads a rate given as a JSON number or string and turns it into units per USD.
+func (a API) value(node any, name string) (decimal.Decimal, error) {
+	var raw string
+
+	switch number := node.(type) {
+	case json.Number:
+		raw = number.String()
+	case string:
+		raw = number
+	default:
+		return decimal.Decimal{}, a.malformedErr("value of "+name, nil)
+	}
+
+	value, err := decimal.NewFromString(raw)
+	if err != nil || !value.IsPositive() {
+		return decimal.Decimal{}, a.malformedErr("value of "+name, err)
+	}
+
+	if a.Spec.Inverted {
+		value = decimal.NewFromInt(1).DivRound(value, invertDigits)
+	}
+
+	return value, nil
+}
+
+func (a API) errorMessage(doc any) string {
+	if a.Spec.ErrorPath == "" || doc == nil {
+		return ""
+	}
+
+	node := doc
+	for _, key := range strings.Split(a.Spec.ErrorPath, ".") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return ""
+		}
+
+		node = object[key]
+	}
+
+	message, _ := node.(string)
+
+	return message
+}
+
+func (a API) malformedErr(what string, err error) error {
+	message := "unexpected " + what + " response"
+	if err != nil {
+		message += ": " + err.Error()
+	}
+
+	return domain.NewProviderError(a.Name, domain.ProviderMalformedPayload, http.StatusOK, message)
+}
+
+// decode keeps the numbers as json.Number so no precision is lost to floats.
+func decode(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// unknownSymbolMessages are the error messages providers report a currency they do not list with.
+var unknownSymbolMessages = []string{
+	"unknown symbol", "unknown currency", "invalid symbol", "invalid currency", "not supported", "unsupported",
+}
+
+// errorKind classifies a failed response. Quota errors are told apart from rate limiting
+// and authorization errors by their message as providers report them with the same statuses.
+// A currency is unknown only when the message says so: a bare 400, 404 or 422 may as well be
+// a broken endpoint or spec, so it counts against the provider instead of unlisting currencies.
+func errorKind(status int, message string) domain.ProviderErrorKind {
+	message = strings.ToLower(message)
+
+	switch {
+	case strings.Contains(message, "quota") || strings.Contains(message, "limit exceeded"):
+		return domain.ProviderQuotaExceeded
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return domain.ProviderUnauthorized
+	case status == http.StatusTooManyRequests:
+		return domain.ProviderRateLimited
+	case containsAny(message, unknownSymbolMessages):
+		return domain.ProviderUnknownSymbol
+	default:
+		return domain.ProviderUpstream
+	}
+}
+
+func containsAny(message string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(message, substring) {
+			return true
+		}
+	}
+
+	return false
+}
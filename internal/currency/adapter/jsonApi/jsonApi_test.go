@@ -0,0 +1,179 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/shopspring/decimal"
+)
+
+func isMalformed(err error) bool {
+	var providerErr *domain.ProviderError
+
+	return errors.As(err, &providerErr) && providerErr.Kind == domain.ProviderMalformedPayload
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		path      string
+		symbol    string
+		want      string
+		wantFound bool
+		wantErr   bool
+	}{
+		{
+			name:      "symbol appended to the path",
+			body:      `{"results": {"EUR": 0.91}}`,
+			path:      "results",
+			symbol:    "EUR",
+			want:      "0.91",
+			wantFound: true,
+		},
+		{
+			name:      "symbol placeholder inside the path",
+			body:      `{"data": {"EUR": {"rate": "0.91"}}}`,
+			path:      "data.{symbol}.rate",
+			symbol:    "EUR",
+			want:      "0.91",
+			wantFound: true,
+		},
+		{
+			name:      "symbol placeholder within a key",
+			body:      `{"quotes": {"USDEUR": 0.91}}`,
+			path:      "quotes.USD{symbol}",
+			symbol:    "EUR",
+			want:      "0.91",
+			wantFound: true,
+		},
+		{
+			name:   "missing symbol is not listed",
+			body:   `{"results": {"EUR": 0.91}}`,
+			path:   "results",
+			symbol: "XYZ",
+		},
+		{
+			name:   "missing key after the symbol is not listed",
+			body:   `{"data": {"EUR": {}}}`,
+			path:   "data.{symbol}.rate",
+			symbol: "EUR",
+		},
+		{
+			name:    "missing key before the symbol is malformed",
+			body:    `{"error": "down"}`,
+			path:    "results",
+			symbol:  "EUR",
+			wantErr: true,
+		},
+		{
+			name:    "non object on the path is malformed",
+			body:    `{"results": [0.91]}`,
+			path:    "results",
+			symbol:  "EUR",
+			wantErr: true,
+		},
+	}
+
+	api := API{Name: "test"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decode([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			node, found, err := api.lookup(doc, tt.path, tt.symbol)
+			if tt.wantErr {
+				if !isMalformed(err) {
+					t.Fatalf("got error %v, want malformed payload", err)
+				}
+
+				return
+			}
+
+			if err != nil || found != tt.wantFound {
+				t.Fatalf("got found %v error %v, want found %v", found, err, tt.wantFound)
+			}
+
+			if found && fmt.Sprint(node) != tt.want {
+				t.Errorf("got %v, want %s", node, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		inverted bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "number keeps every digit", body: `0.91000000000000000001`, want: "0.91000000000000000001"},
+		{name: "string", body: `"0.91"`, want: "0.91"},
+		{name: "inverted", body: `"1.25"`, inverted: true, want: "0.8"},
+		{name: "inverted keeps 16 digits", body: `3`, inverted: true, want: "0.3333333333333333"},
+		{name: "zero", body: `0`, wantErr: true},
+		{name: "negative", body: `"-1"`, wantErr: true},
+		{name: "not a number", body: `"abc"`, wantErr: true},
+		{name: "object", body: `{"rate": 1}`, wantErr: true},
+		{name: "null", body: `null`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decode([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			api := API{Name: "test", Spec: Spec{Inverted: tt.inverted}}
+
+			got, err := api.value(doc, "EUR")
+			if tt.wantErr {
+				if !isMalformed(err) {
+					t.Fatalf("got %s error %v, want malformed payload", got, err)
+				}
+
+				return
+			}
+
+			if err != nil || !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("got %s error %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		message string
+		want    domain.ProviderErrorKind
+	}{
+		{name: "bare bad request", status: 400, message: "Bad Request", want: domain.ProviderUpstream},
+		{name: "bare not found", status: 404, message: "Not Found", want: domain.ProviderUpstream},
+		{name: "bare unprocessable entity", status: 422, message: "Unprocessable Entity", want: domain.ProviderUpstream},
+		{name: "unknown symbol message", status: 400, message: "Unknown symbol: XYZ", want: domain.ProviderUnknownSymbol},
+		{name: "unsupported currency in a 200 body", status: 200, message: "Currency XYZ is not supported", want: domain.ProviderUnknownSymbol},
+		{name: "other error in a 200 body", status: 200, message: "internal error", want: domain.ProviderUpstream},
+		{name: "quota", status: 429, message: "Monthly quota reached", want: domain.ProviderQuotaExceeded},
+		{name: "rate limited", status: 429, message: "Too Many Requests", want: domain.ProviderRateLimited},
+		{name: "unauthorized", status: 401, message: "invalid api key", want: domain.ProviderUnauthorized},
+		{name: "server error", status: 502, message: "Bad Gateway", want: domain.ProviderUpstream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorKind(tt.status, tt.message); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
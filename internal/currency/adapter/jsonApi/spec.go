@@ -0,0 +1,113 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/alemax1/currencies-api/config"
+)
+
+// Placeholders replaced in the URL templates and, for symbolPlaceholder, in the paths.
+const (
+	symbolPlaceholder  = "{symbol}"
+	symbolsPlaceholder = "{symbols}"
+	basePlaceholder    = "{base}"
+	startPlaceholder   = "{start}"
+	endPlaceholder     = "{end}"
+	keyPlaceholder     = "{key}"
+)
+
+// Spec describes the requests sent to a provider and where its responses keep the rates.
+// A path leads to the rate map keyed by currency, or to the value of a single currency
+// when one of its keys contains {symbol}, as in "quotes.USD{symbol}". TimeSeriesPath leads
+// to the map of values keyed by dates formatted with DateLayout. Inverted rates are USD
+// per unit of the currency and are turned into units of the currency per USD.
+type Spec struct {
+	FetchMultiURL  string
+	FetchOneURL    string
+	TimeSeriesURL  string
+	AuthIn         string
+	AuthName       string
+	AuthPrefix     string
+	MultiPath      string
+	OnePath        string
+	TimeSeriesPath string
+	ErrorPath      string
+	Inverted       bool
+	DateLayout     string
+}
+
+// NewSpec builds the spec of a json kind provider from its config.
+func NewSpec(currenciesAPICfg config.CurrenciesAPI, jsonAPICfg config.JSONAPI) (Spec, error) {
+	spec := Spec{
+		FetchMultiURL:  currenciesAPICfg.FetchMultiURL,
+		FetchOneURL:    currenciesAPICfg.FetchOneURL,
+		TimeSeriesURL:  currenciesAPICfg.TimeSeriesURL,
+		AuthIn:         jsonAPICfg.AuthIn,
+		AuthName:       jsonAPICfg.AuthName,
+		AuthPrefix:     jsonAPICfg.AuthPrefix,
+		MultiPath:      jsonAPICfg.MultiPath,
+		OnePath:        jsonAPICfg.OnePath,
+		TimeSeriesPath: jsonAPICfg.TimeSeriesPath,
+		ErrorPath:      jsonAPICfg.ErrorPath,
+		DateLayout:     jsonAPICfg.DateLayout,
+	}
+
+	switch jsonAPICfg.Quote {
+	case config.QuotePerUSD:
+	case config.USDPerQuote:
+		spec.Inverted = true
+	default:
+		return Spec{}, fmt.Errorf("unknown quote: %s", jsonAPICfg.Quote)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return Spec{}, err
+	}
+
+	return spec, nil
+}
+
+func (s Spec) Validate() error {
+	var errs []error
+
+	switch s.AuthIn {
+	case config.QueryAuth, config.HeaderAuth:
+		if s.AuthName == "" {
+			errs = append(errs, errors.New("auth name is required"))
+		}
+	case config.NoAuth:
+	default:
+		errs = append(errs, fmt.Errorf("unknown auth placement: %s", s.AuthIn))
+	}
+
+	if s.FetchMultiURL == "" || s.MultiPath == "" {
+		errs = append(errs, errors.New("multi fetch url and path are required"))
+	}
+
+	if s.FetchOneURL == "" || s.OnePath == "" {
+		errs = append(errs, errors.New("fetch one url and path are required"))
+	}
+
+	if s.TimeSeriesURL != "" && s.TimeSeriesPath == "" {
+		errs = append(errs, errors.New("time series path is required"))
+	}
+
+	if s.DateLayout == "" {
+		errs = append(errs, errors.New("date layout is required"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// expand fills the URL template, the values are query escaped.
+func expand(template string, values map[string]string) string {
+	oldnew := make([]string, 0, 2*len(values))
+	for placeholder, value := range values {
+		oldnew = append(oldnew, placeholder, url.QueryEscape(value))
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(template)
+}
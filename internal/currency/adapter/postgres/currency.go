@@ -5,11 +5,21 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/alemax1/currencies-api/internal/currency/domain"
 	"github.com/shopspring/decimal"
 )
 
+// currencySelect reads the currencies aliased c with the value of an active override in place of the provider value.
+// An override makes a currency available, but a currency suspended for leaving its peg stays
+// unavailable under an override until it returns to its band or the suspension is lifted.
+const currencySelect = `SELECT c.id, c.name, c.type, COALESCE(o.value_usd, c.value_usd),
+	NOT c.is_suspended AND (o.currency_id IS NOT NULL OR c.is_available), c.precision,
+	CASE WHEN o.currency_id IS NULL THEN COALESCE(c.provider, '') ELSE '` + domain.OverrideProvider + `' END,
+	CASE WHEN o.currency_id IS NULL THEN c.confidence END, c.priority, c.updated_at
+	FROM currencies c LEFT JOIN rate_overrides o ON ` + activeOverrideCondition
+
 type Currency struct {
 	*DBExecutor
 }
@@ -27,11 +37,12 @@ func (c Currency) AddEmptyCurrency(ctx context.Context, currency domain.Currency
 	currency.IsAvailable = false
 
 	if err := c.db.QueryRowContext(ctx,
-		"INSERT INTO currencies(type, name, is_available, value_usd) VALUES($1, $2, $3, $4) RETURNING id",
+		"INSERT INTO currencies(type, name, is_available, value_usd, precision) VALUES($1, $2, $3, $4, $5) RETURNING id",
 		currency.Type,
 		currency.Name,
 		currency.IsAvailable,
 		currency.ValueUSD,
+		currency.Precision,
 	).Scan(
 		&id,
 	); err != nil {
@@ -66,12 +77,11 @@ func (c Currency) UpdateCurrencyAvailability(ctx context.Context, name string, i
 	return nil
 }
 
-func (c Currency) UpdateCurrencyByName(ctx context.Context, currency domain.CurrencyUpdateData) error {
+func (c Currency) UpdateCurrencyPriority(ctx context.Context, name string, priority int32) error {
 	result, err := c.db.ExecContext(ctx,
-		"UPDATE currencies SET value_usd=$1, is_available=$2, updated_at=CURRENT_TIMESTAMP WHERE name=$3",
-		currency.ValueUSD,
-		currency.IsAvailable,
-		currency.Name,
+		"UPDATE currencies SET priority=$1 WHERE name=$2",
+		priority,
+		name,
 	)
 	if err != nil {
 		return newExecContextErr(err)
@@ -82,7 +92,58 @@ func (c Currency) UpdateCurrencyByName(ctx context.Context, currency domain.Curr
 		return newUpdatedRowsErr(err)
 	}
 	if updatedRows == 0 {
-		return domain.NewServiceError(domain.ErrNothingUpdated, domain.Client)
+		return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return nil
+}
+
+// UpdateCurrencyByName stores the new value and appends it to the rates history in one transaction.
+// The currency is marked updated at the time the value was observed, as the modification time of a
+// rate file, so a value served from an old source shows its age.
+// A value observed at the same time by the same provider, as an unchanged rate file, is recorded once.
+// An override of the currency is kept as is and still takes precedence on reads.
+func (c Currency) UpdateCurrencyByName(ctx context.Context, currency domain.CurrencyUpdateData) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		"UPDATE currencies SET value_usd=$1, is_available=$2, provider=$3, confidence=$4, updated_at=$5 WHERE name=$6 RETURNING id",
+		currency.ValueUSD,
+		currency.IsAvailable,
+		currency.Provider,
+		currency.Confidence,
+		currency.FetchedAt,
+		currency.Name,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingUpdated, domain.Client)
+		}
+
+		return newExecContextErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO currency_rates_history(currency_id, value_usd, provider, fetched_at, confidence) VALUES($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING`,
+		id,
+		currency.ValueUSD,
+		currency.Provider,
+		currency.FetchedAt,
+		currency.Confidence,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
 	}
 
 	return nil
@@ -90,7 +151,8 @@ func (c Currency) UpdateCurrencyByName(ctx context.Context, currency domain.Curr
 
 func (c Currency) GetCurrenciesByType(ctx context.Context, tp domain.CurrencyType) ([]domain.Currency, error) {
 	rows, err := c.db.QueryContext(ctx,
-		"SELECT id, name, type, value_usd, is_available FROM currencies WHERE type=$1",
+		`SELECT id, name, type, value_usd, is_available, precision, COALESCE(provider, ''), confidence, priority, updated_at
+		FROM currencies WHERE type=$1`,
 		tp,
 	)
 	if err != nil {
@@ -109,6 +171,11 @@ func (c Currency) GetCurrenciesByType(ctx context.Context, tp domain.CurrencyTyp
 			&currency.Type,
 			&currency.ValueUSD,
 			&currency.IsAvailable,
+			&currency.Precision,
+			&currency.Provider,
+			&currency.Confidence,
+			&currency.Priority,
+			&currency.UpdatedAt,
 		); err != nil {
 			return nil, newScanErr(err)
 		}
@@ -126,25 +193,123 @@ func (c Currency) GetCurrency(ctx context.Context, name string) (domain.Currency
 	var currency domain.Currency
 
 	if err := c.db.QueryRowContext(ctx,
-		"SELECT id, name, type, value_usd, is_available FROM currencies WHERE name=$1", name,
+		currencySelect+" WHERE c.name=$1", name,
 	).Scan(
 		&currency.ID,
 		&currency.Name,
 		&currency.Type,
 		&currency.ValueUSD,
 		&currency.IsAvailable,
+		&currency.Precision,
+		&currency.Provider,
+		&currency.Confidence,
+		&currency.Priority,
+		&currency.UpdatedAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Currency{}, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
 		}
+
+		return domain.Currency{}, newScanErr(err)
 	}
 
 	return currency, nil
 }
 
+// GetCurrencyAt returns the currency with the last value recorded in history at or before the given instant,
+// falling back to the close of the last finished aggregate once raw observations are purged.
+// A currency that had a recorded rate at that instant is reported as available.
+func (c Currency) GetCurrencyAt(ctx context.Context, name string, at time.Time) (domain.Currency, error) {
+	var (
+		currency domain.Currency
+		value    decimal.NullDecimal
+	)
+
+	if err := c.db.QueryRowContext(ctx,
+		`SELECT c.id, c.name, c.type, c.precision, h.value_usd FROM currencies c
+		LEFT JOIN LATERAL (
+			SELECT value_usd FROM (
+				(SELECT value_usd, fetched_at AS observed_at, 0 AS priority FROM currency_rates_history
+					WHERE currency_id=c.id AND fetched_at<=$2
+					ORDER BY fetched_at DESC LIMIT 1)
+				UNION ALL
+				(SELECT close, bucket_start + CASE resolution WHEN 'hour' THEN interval '1 hour' ELSE interval '1 day' END, 1
+					FROM currency_rates_aggregates
+					WHERE currency_id=c.id
+						AND bucket_start + CASE resolution WHEN 'hour' THEN interval '1 hour' ELSE interval '1 day' END<=$2
+					ORDER BY bucket_start DESC LIMIT 1)
+			) o
+			ORDER BY observed_at DESC, priority LIMIT 1
+		) h ON true
+		WHERE c.name=$1`,
+		name,
+		at,
+	).Scan(
+		&currency.ID,
+		&currency.Name,
+		&currency.Type,
+		&currency.Precision,
+		&value,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Currency{}, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return domain.Currency{}, newScanErr(err)
+	}
+
+	if !value.Valid {
+		return domain.Currency{}, domain.NewServiceError(domain.ErrNoRateAtTime, domain.Client)
+	}
+
+	currency.ValueUSD = value.Decimal
+	currency.IsAvailable = true
+
+	return currency, nil
+}
+
+func (c Currency) GetCurrenciesByNames(ctx context.Context, names []string) ([]domain.Currency, error) {
+	rows, err := c.db.QueryContext(ctx,
+		currencySelect+" WHERE c.name=ANY($1)",
+		names,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var currencies []domain.Currency
+
+	for rows.Next() {
+		var currency domain.Currency
+
+		if err := rows.Scan(
+			&currency.ID,
+			&currency.Name,
+			&currency.Type,
+			&currency.ValueUSD,
+			&currency.IsAvailable,
+			&currency.Precision,
+			&currency.Provider,
+			&currency.Confidence,
+			&currency.Priority,
+			&currency.UpdatedAt,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		currencies = append(currencies, currency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return currencies, nil
+}
+
 func (c Currency) GetAll(ctx context.Context) ([]domain.Currency, error) {
 	rows, err := c.db.QueryContext(ctx,
-		"SELECT id, name, type, value_usd, is_available FROM currencies",
+		currencySelect,
 	)
 	if err != nil {
 		return nil, newQueryErr(err)
@@ -162,6 +327,11 @@ func (c Currency) GetAll(ctx context.Context) ([]domain.Currency, error) {
 			&currency.Type,
 			&currency.ValueUSD,
 			&currency.IsAvailable,
+			&currency.Precision,
+			&currency.Provider,
+			&currency.Confidence,
+			&currency.Priority,
+			&currency.UpdatedAt,
 		); err != nil {
 			return nil, newScanErr(err)
 		}
@@ -2,7 +2,10 @@ package postgres
 
 import "fmt"
 
-const duplicateErrorCode = "23505"
+const (
+	duplicateErrorCode  = "23505"
+	foreignKeyErrorCode = "23503"
+)
 
 func newExecContextErr(err error) error {
 	return fmt.Errorf("exec context: %w", err)
@@ -23,3 +26,11 @@ func newScanErr(err error) error {
 func newRowsErr(err error) error {
 	return fmt.Errorf("rows: %w", err)
 }
+
+func newBeginTxErr(err error) error {
+	return fmt.Errorf("begin tx: %w", err)
+}
+
+func newCommitErr(err error) error {
+	return fmt.Errorf("commit: %w", err)
+}
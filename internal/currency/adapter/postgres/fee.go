@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type Fee struct {
+	*DBExecutor
+}
+
+func NewFee(executor *DBExecutor) *Fee {
+	return &Fee{
+		DBExecutor: executor,
+	}
+}
+
+func (f Fee) GetFeeSchedules(ctx context.Context) ([]domain.FeeSchedule, error) {
+	rows, err := f.db.QueryContext(ctx,
+		"SELECT id, from_name, to_name, fixed, percentage, min_fee, max_fee FROM fee_schedules ORDER BY id",
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var (
+		schedules []domain.FeeSchedule
+		positions = make(map[int64]int)
+	)
+
+	for rows.Next() {
+		var schedule domain.FeeSchedule
+
+		if err := rows.Scan(
+			&schedule.ID,
+			&schedule.From,
+			&schedule.To,
+			&schedule.Fixed,
+			&schedule.Percentage,
+			&schedule.MinFee,
+			&schedule.MaxFee,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		positions[schedule.ID] = len(schedules)
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	tierRows, err := f.db.QueryContext(ctx,
+		"SELECT schedule_id, from_amount, fixed, percentage FROM fee_tiers ORDER BY schedule_id, from_amount",
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer tierRows.Close()
+
+	for tierRows.Next() {
+		var (
+			scheduleID int64
+			tier       domain.FeeTier
+		)
+
+		if err := tierRows.Scan(
+			&scheduleID,
+			&tier.FromAmount,
+			&tier.Fixed,
+			&tier.Percentage,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		if position, ok := positions[scheduleID]; ok {
+			schedules[position].Tiers = append(schedules[position].Tiers, tier)
+		}
+	}
+	if err := tierRows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return schedules, nil
+}
+
+// SaveFeeSchedule creates or replaces the schedule of the pair together with its tiers.
+func (f Fee) SaveFeeSchedule(ctx context.Context, schedule domain.FeeSchedule) (int64, error) {
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO fee_schedules(from_name, to_name, fixed, percentage, min_fee, max_fee) VALUES($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (from_name, to_name) DO UPDATE SET
+			fixed=EXCLUDED.fixed,
+			percentage=EXCLUDED.percentage,
+			min_fee=EXCLUDED.min_fee,
+			max_fee=EXCLUDED.max_fee,
+			updated_at=CURRENT_TIMESTAMP
+		RETURNING id`,
+		schedule.From,
+		schedule.To,
+		schedule.Fixed,
+		schedule.Percentage,
+		schedule.MinFee,
+		schedule.MaxFee,
+	).Scan(
+		&id,
+	); err != nil {
+		if strings.Contains(err.Error(), foreignKeyErrorCode) {
+			return 0, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return 0, newScanErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM fee_tiers WHERE schedule_id=$1", id); err != nil {
+		return 0, newExecContextErr(err)
+	}
+
+	for _, tier := range schedule.Tiers {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO fee_tiers(schedule_id, from_amount, fixed, percentage) VALUES($1, $2, $3, $4)",
+			id,
+			tier.FromAmount,
+			tier.Fixed,
+			tier.Percentage,
+		); err != nil {
+			if strings.Contains(err.Error(), duplicateErrorCode) {
+				return 0, domain.NewServiceError(domain.ErrDuplicateValue, domain.Client)
+			}
+
+			return 0, newExecContextErr(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, newCommitErr(err)
+	}
+
+	return id, nil
+}
+
+func (f Fee) DeleteFeeSchedule(ctx context.Context, id int64) error {
+	result, err := f.db.ExecContext(ctx,
+		"DELETE FROM fee_schedules WHERE id=$1",
+		id,
+	)
+	if err != nil {
+		return newExecContextErr(err)
+	}
+
+	deletedRows, err := result.RowsAffected()
+	if err != nil {
+		return newUpdatedRowsErr(err)
+	}
+	if deletedRows == 0 {
+		return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return nil
+}
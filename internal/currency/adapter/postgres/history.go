@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type History struct {
+	*DBExecutor
+}
+
+func NewHistory(executor *DBExecutor) *History {
+	return &History{
+		DBExecutor: executor,
+	}
+}
+
+// AddObservations stores observations of the currency, skipping every day that already has
+// raw or aggregated history so that backfilled data never overwrites recorded rates.
+func (h History) AddObservations(ctx context.Context, currencyID int64, observations []domain.CurrencyWithValue) (int64, error) {
+	var inserted int64
+
+	for _, observation := range observations {
+		result, err := h.db.ExecContext(ctx,
+			`INSERT INTO currency_rates_history(currency_id, value_usd, provider, fetched_at)
+			SELECT $1, $2, $3, $4
+			WHERE NOT EXISTS (
+				SELECT 1 FROM currency_rates_history
+				WHERE currency_id=$1 AND fetched_at>=date_trunc('day', $4::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+					AND fetched_at<(date_trunc('day', $4::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC') + interval '1 day'
+			) AND NOT EXISTS (
+				SELECT 1 FROM currency_rates_aggregates
+				WHERE currency_id=$1 AND bucket_start>=date_trunc('day', $4::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+					AND bucket_start<(date_trunc('day', $4::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC') + interval '1 day'
+			)
+			ON CONFLICT DO NOTHING`,
+			currencyID,
+			observation.Value,
+			observation.Provider,
+			observation.FetchedAt,
+		)
+		if err != nil {
+			return inserted, newExecContextErr(err)
+		}
+
+		insertedRows, err := result.RowsAffected()
+		if err != nil {
+			return inserted, newUpdatedRowsErr(err)
+		}
+
+		inserted += insertedRows
+	}
+
+	return inserted, nil
+}
+
+// GetBars returns the observations recorded in [from, to) ordered by time, preceded by
+// the last observation made before from, if any. Periods whose raw observations were
+// already purged are filled with the stored OHLC of the aggregates.
+func (h History) GetBars(ctx context.Context, currencyID int64, from, to time.Time) ([]domain.RateBar, error) {
+	bars, err := h.getAggregateBars(ctx, currencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := h.db.QueryContext(ctx,
+		`(SELECT value_usd, fetched_at FROM currency_rates_history
+			WHERE currency_id=$1 AND fetched_at<$2
+			ORDER BY fetched_at DESC LIMIT 1)
+		UNION ALL
+		(SELECT value_usd, fetched_at FROM currency_rates_history
+			WHERE currency_id=$1 AND fetched_at>=$2 AND fetched_at<$3)
+		ORDER BY fetched_at`,
+		currencyID,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var observation domain.RateObservation
+
+		if err := rows.Scan(
+			&observation.ValueUSD,
+			&observation.ObservedAt,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		bars = append(bars, observation.Bar())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return bars, nil
+}
+
+// getAggregateBars returns aggregates older than the oldest raw observation,
+// preferring hourly buckets over daily ones.
+func (h History) getAggregateBars(ctx context.Context, currencyID int64, from, to time.Time) ([]domain.RateBar, error) {
+	rows, err := h.db.QueryContext(ctx,
+		`WITH bounds AS (
+			SELECT
+				COALESCE((SELECT MIN(fetched_at) FROM currency_rates_history WHERE currency_id=$1), 'infinity') AS raw_start,
+				COALESCE((SELECT MIN(bucket_start) FROM currency_rates_aggregates WHERE currency_id=$1 AND resolution='hour'), 'infinity') AS hourly_start
+		)
+		SELECT a.resolution, a.bucket_start, a.open, a.high, a.low, a.close
+		FROM currency_rates_aggregates a, bounds b
+		WHERE a.currency_id=$1
+			AND a.bucket_start>=$2::timestamptz - interval '1 day' AND a.bucket_start<$3
+			AND a.bucket_start<b.raw_start
+			AND (a.resolution='hour' OR a.bucket_start + interval '1 day'<=LEAST(b.hourly_start, b.raw_start))
+		ORDER BY a.bucket_start, a.resolution DESC`,
+		currencyID,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var bars []domain.RateBar
+
+	for rows.Next() {
+		var aggregate domain.RateAggregate
+
+		if err := rows.Scan(
+			&aggregate.Resolution,
+			&aggregate.BucketStart,
+			&aggregate.Open,
+			&aggregate.High,
+			&aggregate.Low,
+			&aggregate.Close,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		bars = append(bars, aggregate.Bar())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return bars, nil
+}
+
+// Downsample rolls raw observations made in [from, to) up into aggregates of the given resolution,
+// for the currency or, when currencyID is zero, for every currency.
+// Buckets are recomputed from scratch, so running it repeatedly over the same window is safe.
+func (h History) Downsample(ctx context.Context, currencyID int64, resolution domain.Resolution, from, to time.Time) (int64, error) {
+	result, err := h.db.ExecContext(ctx,
+		`INSERT INTO currency_rates_aggregates(currency_id, resolution, bucket_start, open, high, low, close, samples)
+		SELECT
+			currency_id,
+			$1::text::rate_resolutions,
+			date_trunc($1::text, fetched_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+			(array_agg(value_usd ORDER BY fetched_at))[1],
+			MAX(value_usd),
+			MIN(value_usd),
+			(array_agg(value_usd ORDER BY fetched_at DESC))[1],
+			COUNT(*)
+		FROM currency_rates_history
+		WHERE fetched_at>=$2 AND fetched_at<$3 AND ($4=0 OR currency_id=$4)
+		GROUP BY currency_id, bucket
+		ON CONFLICT (currency_id, resolution, bucket_start) DO UPDATE SET
+			open=EXCLUDED.open,
+			high=EXCLUDED.high,
+			low=EXCLUDED.low,
+			close=EXCLUDED.close,
+			samples=EXCLUDED.samples,
+			updated_at=CURRENT_TIMESTAMP`,
+		resolution,
+		from,
+		to,
+		currencyID,
+	)
+	if err != nil {
+		return 0, newExecContextErr(err)
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return 0, newUpdatedRowsErr(err)
+	}
+
+	return affectedRows, nil
+}
+
+func (h History) PurgeObservations(ctx context.Context, before time.Time) (int64, error) {
+	result, err := h.db.ExecContext(ctx,
+		"DELETE FROM currency_rates_history WHERE fetched_at<$1",
+		before,
+	)
+	if err != nil {
+		return 0, newExecContextErr(err)
+	}
+
+	deletedRows, err := result.RowsAffected()
+	if err != nil {
+		return 0, newUpdatedRowsErr(err)
+	}
+
+	return deletedRows, nil
+}
+
+func (h History) PurgeAggregates(ctx context.Context, resolution domain.Resolution, before time.Time) (int64, error) {
+	result, err := h.db.ExecContext(ctx,
+		"DELETE FROM currency_rates_aggregates WHERE resolution=$1 AND bucket_start<$2",
+		resolution,
+		before,
+	)
+	if err != nil {
+		return 0, newExecContextErr(err)
+	}
+
+	deletedRows, err := result.RowsAffected()
+	if err != nil {
+		return 0, newUpdatedRowsErr(err)
+	}
+
+	return deletedRows, nil
+}
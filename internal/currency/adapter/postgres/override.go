@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// activeOverrideCondition matches the override of the currency aliased c that has not expired yet.
+const activeOverrideCondition = "o.currency_id=c.id AND (o.expires_at IS NULL OR o.expires_at>CURRENT_TIMESTAMP)"
+
+type Override struct {
+	*DBExecutor
+}
+
+func NewOverride(executor *DBExecutor) *Override {
+	return &Override{
+		DBExecutor: executor,
+	}
+}
+
+func (o Override) GetOverrides(ctx context.Context) ([]domain.RateOverride, error) {
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT c.name, o.value_usd, o.reason, o.set_by, o.expires_at, o.created_at
+		FROM rate_overrides o JOIN currencies c ON `+activeOverrideCondition+`
+		ORDER BY c.name`,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var overrides []domain.RateOverride
+
+	for rows.Next() {
+		var (
+			override  domain.RateOverride
+			expiresAt sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&override.Currency,
+			&override.ValueUSD,
+			&override.Reason,
+			&override.Actor,
+			&expiresAt,
+			&override.CreatedAt,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		override.ExpiresAt = expiresAt.Time
+
+		overrides = append(overrides, override)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return overrides, nil
+}
+
+// SetOverride creates or replaces the override of the currency and records it in the audit trail in one transaction.
+func (o Override) SetOverride(ctx context.Context, override domain.RateOverride) error {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	expiresAt := toNullTime(override.ExpiresAt)
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO rate_overrides(currency_id, value_usd, reason, set_by, expires_at)
+		SELECT id, $2, $3, $4, $5 FROM currencies WHERE name=$1
+		ON CONFLICT (currency_id) DO UPDATE SET value_usd=EXCLUDED.value_usd, reason=EXCLUDED.reason,
+			set_by=EXCLUDED.set_by, expires_at=EXCLUDED.expires_at, created_at=CURRENT_TIMESTAMP
+		RETURNING currency_id`,
+		override.Currency,
+		override.ValueUSD,
+		override.Reason,
+		override.Actor,
+		expiresAt,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return newScanErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO rate_overrides_audit(currency_id, action, value_usd, reason, actor, expires_at) VALUES($1, $2, $3, $4, $5, $6)",
+		id,
+		domain.OverrideSet,
+		override.ValueUSD,
+		override.Reason,
+		override.Actor,
+		expiresAt,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+// ClearOverride removes the override of the currency, expired or not, and records it in the audit trail.
+func (o Override) ClearOverride(ctx context.Context, name, actor, reason string) error {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		"DELETE FROM rate_overrides o USING currencies c WHERE o.currency_id=c.id AND c.name=$1 RETURNING o.currency_id",
+		name,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return newScanErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO rate_overrides_audit(currency_id, action, reason, actor) VALUES($1, $2, $3, $4)",
+		id,
+		domain.OverrideClear,
+		reason,
+		actor,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+// GetOverrideAudit returns the audit trail of the currency, newest entries first.
+func (o Override) GetOverrideAudit(ctx context.Context, name string) ([]domain.OverrideAuditEntry, error) {
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT a.id, c.name, a.action, a.value_usd, a.reason, a.actor, a.expires_at, a.created_at
+		FROM rate_overrides_audit a JOIN currencies c ON c.id=a.currency_id
+		WHERE c.name=$1
+		ORDER BY a.created_at DESC, a.id DESC`,
+		name,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var entries []domain.OverrideAuditEntry
+
+	for rows.Next() {
+		var (
+			entry     domain.OverrideAuditEntry
+			expiresAt sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Currency,
+			&entry.Action,
+			&entry.ValueUSD,
+			&entry.Reason,
+			&entry.Actor,
+			&expiresAt,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		entry.ExpiresAt = expiresAt.Time
+
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return entries, nil
+}
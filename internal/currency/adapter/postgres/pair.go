@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type PairPolicy struct {
+	*DBExecutor
+}
+
+func NewPairPolicy(executor *DBExecutor) *PairPolicy {
+	return &PairPolicy{
+		DBExecutor: executor,
+	}
+}
+
+func (p PairPolicy) GetPairPolicies(ctx context.Context) ([]domain.PairPolicy, error) {
+	rows, err := p.db.QueryContext(ctx,
+		"SELECT id, from_name, to_name, from_type, to_type, is_allowed FROM currency_pairs ORDER BY id",
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var policies []domain.PairPolicy
+
+	for rows.Next() {
+		var (
+			policy           domain.PairPolicy
+			from, to         sql.NullString
+			fromType, toType sql.NullString
+		)
+
+		if err := rows.Scan(
+			&policy.ID,
+			&from,
+			&to,
+			&fromType,
+			&toType,
+			&policy.IsAllowed,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		policy.From = from.String
+		policy.To = to.String
+		policy.FromType = domain.CurrencyType(fromType.String)
+		policy.ToType = domain.CurrencyType(toType.String)
+
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return policies, nil
+}
+
+// SavePairPolicy creates the policy or updates the existing one for the same pair or types.
+func (p PairPolicy) SavePairPolicy(ctx context.Context, policy domain.PairPolicy) (int64, error) {
+	query := `INSERT INTO currency_pairs(from_type, to_type, is_allowed) VALUES($1, $2, $3)
+		ON CONFLICT (from_type, to_type) WHERE from_type IS NOT NULL
+		DO UPDATE SET is_allowed=EXCLUDED.is_allowed, updated_at=CURRENT_TIMESTAMP
+		RETURNING id`
+	args := []any{policy.FromType, policy.ToType, policy.IsAllowed}
+
+	if policy.IsPairRule() {
+		query = `INSERT INTO currency_pairs(from_name, to_name, is_allowed) VALUES($1, $2, $3)
+			ON CONFLICT (from_name, to_name) WHERE from_name IS NOT NULL
+			DO UPDATE SET is_allowed=EXCLUDED.is_allowed, updated_at=CURRENT_TIMESTAMP
+			RETURNING id`
+		args = []any{policy.From, policy.To, policy.IsAllowed}
+	}
+
+	var id int64
+
+	if err := p.db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		if strings.Contains(err.Error(), foreignKeyErrorCode) {
+			return 0, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return 0, newScanErr(err)
+	}
+
+	return id, nil
+}
+
+func (p PairPolicy) DeletePairPolicy(ctx context.Context, id int64) error {
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM currency_pairs WHERE id=$1",
+		id,
+	)
+	if err != nil {
+		return newExecContextErr(err)
+	}
+
+	deletedRows, err := result.RowsAffected()
+	if err != nil {
+		return newUpdatedRowsErr(err)
+	}
+	if deletedRows == 0 {
+		return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/shopspring/decimal"
+)
+
+// openDepegCondition matches the open depeg event of the currency aliased c.
+const openDepegCondition = "e.currency_id=c.id AND e.ended_at IS NULL"
+
+type Peg struct {
+	*DBExecutor
+}
+
+func NewPeg(executor *DBExecutor) *Peg {
+	return &Peg{
+		DBExecutor: executor,
+	}
+}
+
+// GetPegs returns the pegged currencies with the provider values of the currency and its target.
+func (p Peg) GetPegs(ctx context.Context) ([]domain.Peg, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT c.name, c.peg_currency, c.peg_tolerance, c.peg_auto_suspend, e.id IS NOT NULL, c.is_suspended,
+			c.value_usd, t.value_usd
+		FROM currencies c
+		JOIN currencies t ON t.name=c.peg_currency
+		LEFT JOIN depeg_events e ON `+openDepegCondition+`
+		WHERE c.peg_currency IS NOT NULL
+		ORDER BY c.name`,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var pegs []domain.Peg
+
+	for rows.Next() {
+		var peg domain.Peg
+
+		if err := rows.Scan(
+			&peg.Currency,
+			&peg.Target,
+			&peg.Tolerance,
+			&peg.AutoSuspend,
+			&peg.Depegged,
+			&peg.Suspended,
+			&peg.ValueUSD,
+			&peg.TargetValueUSD,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		pegs = append(pegs, peg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return pegs, nil
+}
+
+// SetPeg creates or replaces the peg of the currency. Turning auto suspend on or off applies
+// right away to an open depeg event.
+func (p Peg) SetPeg(ctx context.Context, peg domain.Peg) error {
+	result, err := p.db.ExecContext(ctx,
+		`UPDATE currencies c SET peg_currency=$2, peg_tolerance=$3, peg_auto_suspend=$4,
+			is_suspended=$4 AND EXISTS (SELECT 1 FROM depeg_events e WHERE `+openDepegCondition+`)
+		WHERE c.name=$1 AND EXISTS (SELECT 1 FROM currencies t WHERE t.name=$2)`,
+		peg.Currency,
+		peg.Target,
+		peg.Tolerance,
+		peg.AutoSuspend,
+	)
+	if err != nil {
+		return newExecContextErr(err)
+	}
+
+	updatedRows, err := result.RowsAffected()
+	if err != nil {
+		return newUpdatedRowsErr(err)
+	}
+	if updatedRows == 0 {
+		return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return nil
+}
+
+// ClearPeg removes the peg of the currency, closing its open depeg event and lifting the suspension.
+func (p Peg) ClearPeg(ctx context.Context, name string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		`UPDATE currencies SET peg_currency=NULL, peg_tolerance=NULL, peg_auto_suspend=false, is_suspended=false
+		WHERE name=$1 AND peg_currency IS NOT NULL
+		RETURNING id`,
+		name,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return newScanErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE depeg_events SET ended_at=CURRENT_TIMESTAMP WHERE currency_id=$1 AND ended_at IS NULL",
+		id,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+// OpenDepegEvent records the currency leaving its peg band and suspends it when the peg asks so, in one transaction.
+func (p Peg) OpenDepegEvent(ctx context.Context, peg domain.Peg) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		"UPDATE currencies SET is_suspended=$2 WHERE name=$1 RETURNING id",
+		peg.Currency,
+		peg.AutoSuspend,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return newScanErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO depeg_events(currency_id, peg_currency, tolerance, start_price, max_deviation, suspended)
+		VALUES($1, $2, $3, $4, $5, $6)`,
+		id,
+		peg.Target,
+		peg.Tolerance,
+		peg.Price(),
+		peg.Deviation(),
+		peg.AutoSuspend,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+// UpdateDepegEvent raises the max deviation of the open depeg event of the currency.
+func (p Peg) UpdateDepegEvent(ctx context.Context, name string, deviation decimal.Decimal) error {
+	if _, err := p.db.ExecContext(ctx,
+		`UPDATE depeg_events e SET max_deviation=GREATEST(e.max_deviation, $2)
+		FROM currencies c WHERE c.name=$1 AND `+openDepegCondition,
+		name,
+		deviation,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	return nil
+}
+
+// CloseDepegEvent records the currency back in its peg band and lifts the suspension in one transaction.
+func (p Peg) CloseDepegEvent(ctx context.Context, name string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		"UPDATE currencies SET is_suspended=false WHERE name=$1 RETURNING id",
+		name,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return newScanErr(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE depeg_events SET ended_at=CURRENT_TIMESTAMP WHERE currency_id=$1 AND ended_at IS NULL",
+		id,
+	); err != nil {
+		return newExecContextErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+// GetDepegEvents returns the depeg events of the currency, newest first.
+func (p Peg) GetDepegEvents(ctx context.Context, name string) ([]domain.DepegEvent, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT e.id, c.name, e.peg_currency, e.tolerance, e.start_price, e.max_deviation, e.suspended,
+			e.started_at, e.ended_at
+		FROM depeg_events e JOIN currencies c ON c.id=e.currency_id
+		WHERE c.name=$1
+		ORDER BY e.started_at DESC, e.id DESC`,
+		name,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var events []domain.DepegEvent
+
+	for rows.Next() {
+		var (
+			event   domain.DepegEvent
+			endedAt sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&event.ID,
+			&event.Currency,
+			&event.Target,
+			&event.Tolerance,
+			&event.StartPrice,
+			&event.MaxDeviation,
+			&event.Suspended,
+			&event.StartedAt,
+			&endedAt,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		event.EndedAt = endedAt.Time
+
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return events, nil
+}
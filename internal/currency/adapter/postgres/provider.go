@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type ProviderHealth struct {
+	*DBExecutor
+}
+
+func NewProviderHealth(executor *DBExecutor) *ProviderHealth {
+	return &ProviderHealth{
+		DBExecutor: executor,
+	}
+}
+
+// SaveProviderHealth replaces the stored state of the providers in one transaction.
+func (p ProviderHealth) SaveProviderHealth(ctx context.Context, health []domain.ProviderHealth) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	for _, h := range health {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO provider_health(provider, state, consecutive_failures, last_latency_ms, avg_latency_ms,
+				last_error, last_success_at, last_failure_at, opened_at, updated_at)
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (provider) DO UPDATE SET state=EXCLUDED.state, consecutive_failures=EXCLUDED.consecutive_failures,
+				last_latency_ms=EXCLUDED.last_latency_ms, avg_latency_ms=EXCLUDED.avg_latency_ms,
+				last_error=EXCLUDED.last_error, last_success_at=EXCLUDED.last_success_at,
+				last_failure_at=EXCLUDED.last_failure_at, opened_at=EXCLUDED.opened_at, updated_at=EXCLUDED.updated_at`,
+			h.Provider,
+			h.State,
+			h.ConsecutiveFailures,
+			h.LastLatency.Milliseconds(),
+			h.AvgLatency.Milliseconds(),
+			h.LastError,
+			toNullTime(h.LastSuccessAt),
+			toNullTime(h.LastFailureAt),
+			toNullTime(h.OpenedAt),
+			h.UpdatedAt,
+		); err != nil {
+			return newExecContextErr(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+func (p ProviderHealth) GetProviderHealth(ctx context.Context) ([]domain.ProviderHealth, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT provider, state, consecutive_failures, last_latency_ms, avg_latency_ms,
+			last_error, last_success_at, last_failure_at, opened_at, updated_at
+		FROM provider_health ORDER BY provider`,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var health []domain.ProviderHealth
+
+	for rows.Next() {
+		var (
+			h                                      domain.ProviderHealth
+			lastLatency, avgLatency                int64
+			lastSuccessAt, lastFailureAt, openedAt sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&h.Provider,
+			&h.State,
+			&h.ConsecutiveFailures,
+			&lastLatency,
+			&avgLatency,
+			&h.LastError,
+			&lastSuccessAt,
+			&lastFailureAt,
+			&openedAt,
+			&h.UpdatedAt,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		h.LastLatency = time.Duration(lastLatency) * time.Millisecond
+		h.AvgLatency = time.Duration(avgLatency) * time.Millisecond
+		h.LastSuccessAt = lastSuccessAt.Time
+		h.LastFailureAt = lastFailureAt.Time
+		h.OpenedAt = openedAt.Time
+
+		health = append(health, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return health, nil
+}
+
+func toNullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
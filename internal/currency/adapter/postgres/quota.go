@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type Quota struct {
+	*DBExecutor
+}
+
+func NewQuota(executor *DBExecutor) *Quota {
+	return &Quota{
+		DBExecutor: executor,
+	}
+}
+
+// AddCalls adds the calls made to the providers to their count of the day.
+func (q Quota) AddCalls(ctx context.Context, day time.Time, calls map[string]int64) error {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newBeginTxErr(err)
+	}
+	defer tx.Rollback()
+
+	for provider, count := range calls {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO provider_calls(provider, day, calls) VALUES($1, $2, $3)
+			ON CONFLICT (provider, day) DO UPDATE SET calls=provider_calls.calls+EXCLUDED.calls`,
+			provider,
+			day,
+			count,
+		); err != nil {
+			return newExecContextErr(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCommitErr(err)
+	}
+
+	return nil
+}
+
+// GetUsage returns the calls made to each provider on the day and in its month so far.
+func (q Quota) GetUsage(ctx context.Context, day time.Time) ([]domain.QuotaUsage, error) {
+	rows, err := q.db.QueryContext(ctx,
+		`SELECT provider, COALESCE(SUM(calls) FILTER (WHERE day=$1), 0), SUM(calls)
+		FROM provider_calls
+		WHERE day>=date_trunc('month', $1::date) AND day<=$1
+		GROUP BY provider
+		ORDER BY provider`,
+		day,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var usage []domain.QuotaUsage
+
+	for rows.Next() {
+		var u domain.QuotaUsage
+
+		if err := rows.Scan(
+			&u.Provider,
+			&u.Daily,
+			&u.Monthly,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type Quote struct {
+	*DBExecutor
+}
+
+func NewQuote(executor *DBExecutor) *Quote {
+	return &Quote{
+		DBExecutor: executor,
+	}
+}
+
+// CreateQuote stores the quote and sets its id and expiry, measured by the database clock.
+func (q Quote) CreateQuote(ctx context.Context, quote domain.Quote, ttl time.Duration) (domain.Quote, error) {
+	if err := q.db.QueryRowContext(ctx,
+		`INSERT INTO quotes(from_name, to_name, value, amount, fee, fee_fixed, fee_percentage, fee_cap_adjustment,
+			net, precision, rounding, mid, bid, ask, markup, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			CURRENT_TIMESTAMP + $16 * interval '1 millisecond')
+		RETURNING id, expires_at`,
+		quote.From,
+		quote.To,
+		quote.Value,
+		quote.Conversion.Amount,
+		quote.Conversion.Fee.Total,
+		quote.Conversion.Fee.Fixed,
+		quote.Conversion.Fee.Percentage,
+		quote.Conversion.Fee.CapAdjustment,
+		quote.Conversion.Net,
+		quote.Conversion.Precision,
+		quote.Conversion.Rounding,
+		quote.Conversion.Mid,
+		quote.Conversion.Bid,
+		quote.Conversion.Ask,
+		quote.Conversion.Markup,
+		ttl.Milliseconds(),
+	).Scan(
+		&quote.ID,
+		&quote.ExpiresAt,
+	); err != nil {
+		return domain.Quote{}, newScanErr(err)
+	}
+
+	return quote, nil
+}
+
+// AcceptQuote marks the quote as accepted and returns it unchanged while it has not expired.
+// Accepting an accepted quote again before expiry returns the same quote.
+func (q Quote) AcceptQuote(ctx context.Context, id string) (domain.Quote, error) {
+	var quote domain.Quote
+
+	if err := q.db.QueryRowContext(ctx,
+		`UPDATE quotes SET accepted_at=COALESCE(accepted_at, CURRENT_TIMESTAMP)
+		WHERE id=$1 AND expires_at>CURRENT_TIMESTAMP
+		RETURNING id, from_name, to_name, value, amount, fee, fee_fixed, fee_percentage, fee_cap_adjustment,
+			net, precision, rounding, mid, bid, ask, markup, expires_at, accepted_at`,
+		id,
+	).Scan(
+		&quote.ID,
+		&quote.From,
+		&quote.To,
+		&quote.Value,
+		&quote.Conversion.Amount,
+		&quote.Conversion.Fee.Total,
+		&quote.Conversion.Fee.Fixed,
+		&quote.Conversion.Fee.Percentage,
+		&quote.Conversion.Fee.CapAdjustment,
+		&quote.Conversion.Net,
+		&quote.Conversion.Precision,
+		&quote.Conversion.Rounding,
+		&quote.Conversion.Mid,
+		&quote.Conversion.Bid,
+		&quote.Conversion.Ask,
+		&quote.Conversion.Markup,
+		&quote.ExpiresAt,
+		&quote.AcceptedAt,
+	); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return domain.Quote{}, newScanErr(err)
+		}
+
+		return domain.Quote{}, q.missingQuoteErr(ctx, id)
+	}
+
+	return quote, nil
+}
+
+// missingQuoteErr tells an expired quote apart from an unknown one.
+func (q Quote) missingQuoteErr(ctx context.Context, id string) error {
+	var exists bool
+
+	if err := q.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM quotes WHERE id=$1)", id,
+	).Scan(
+		&exists,
+	); err != nil {
+		return newScanErr(err)
+	}
+
+	if exists {
+		return domain.NewServiceError(domain.ErrQuoteExpired, domain.Client)
+	}
+
+	return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type Spread struct {
+	*DBExecutor
+}
+
+func NewSpread(executor *DBExecutor) *Spread {
+	return &Spread{
+		DBExecutor: executor,
+	}
+}
+
+func (s Spread) GetSpreads(ctx context.Context) ([]domain.Spread, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, from_name, to_name, currency_name, from_type, to_type, markup FROM spreads ORDER BY id",
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var spreads []domain.Spread
+
+	for rows.Next() {
+		var (
+			spread                 domain.Spread
+			from, to, currencyName sql.NullString
+			fromType, toType       sql.NullString
+		)
+
+		if err := rows.Scan(
+			&spread.ID,
+			&from,
+			&to,
+			&currencyName,
+			&fromType,
+			&toType,
+			&spread.Markup,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		spread.From = from.String
+		spread.To = to.String
+		spread.Currency = currencyName.String
+		spread.FromType = domain.CurrencyType(fromType.String)
+		spread.ToType = domain.CurrencyType(toType.String)
+
+		spreads = append(spreads, spread)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return spreads, nil
+}
+
+// SaveSpread creates the spread or updates the markup of the existing one with the same target.
+func (s Spread) SaveSpread(ctx context.Context, spread domain.Spread) (int64, error) {
+	var (
+		query string
+		args  []any
+	)
+
+	switch {
+	case spread.From != "":
+		query = `INSERT INTO spreads(from_name, to_name, markup) VALUES($1, $2, $3)
+			ON CONFLICT (from_name, to_name) WHERE from_name IS NOT NULL
+			DO UPDATE SET markup=EXCLUDED.markup, updated_at=CURRENT_TIMESTAMP
+			RETURNING id`
+		args = []any{spread.From, spread.To, spread.Markup}
+	case spread.Currency != "":
+		query = `INSERT INTO spreads(currency_name, markup) VALUES($1, $2)
+			ON CONFLICT (currency_name) WHERE currency_name IS NOT NULL
+			DO UPDATE SET markup=EXCLUDED.markup, updated_at=CURRENT_TIMESTAMP
+			RETURNING id`
+		args = []any{spread.Currency, spread.Markup}
+	default:
+		query = `INSERT INTO spreads(from_type, to_type, markup) VALUES($1, $2, $3)
+			ON CONFLICT (from_type, to_type) WHERE from_type IS NOT NULL
+			DO UPDATE SET markup=EXCLUDED.markup, updated_at=CURRENT_TIMESTAMP
+			RETURNING id`
+		args = []any{spread.FromType, spread.ToType, spread.Markup}
+	}
+
+	var id int64
+
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		if strings.Contains(err.Error(), foreignKeyErrorCode) {
+			return 0, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return 0, newScanErr(err)
+	}
+
+	return id, nil
+}
+
+func (s Spread) DeleteSpread(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM spreads WHERE id=$1",
+		id,
+	)
+	if err != nil {
+		return newExecContextErr(err)
+	}
+
+	deletedRows, err := result.RowsAffected()
+	if err != nil {
+		return newUpdatedRowsErr(err)
+	}
+	if deletedRows == 0 {
+		return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return nil
+}
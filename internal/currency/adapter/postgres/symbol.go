@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type ProviderSymbol struct {
+	*DBExecutor
+}
+
+func NewProviderSymbol(executor *DBExecutor) *ProviderSymbol {
+	return &ProviderSymbol{
+		DBExecutor: executor,
+	}
+}
+
+// GetProviderSymbols returns the symbols of the provider, or of every provider when it is empty.
+func (p ProviderSymbol) GetProviderSymbols(ctx context.Context, provider string) ([]domain.ProviderSymbol, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT s.provider, c.name, s.symbol
+		FROM provider_symbols s JOIN currencies c ON c.id=s.currency_id
+		WHERE $1='' OR s.provider=$1
+		ORDER BY s.provider, c.name`,
+		provider,
+	)
+	if err != nil {
+		return nil, newQueryErr(err)
+	}
+	defer rows.Close()
+
+	var symbols []domain.ProviderSymbol
+
+	for rows.Next() {
+		var symbol domain.ProviderSymbol
+
+		if err := rows.Scan(
+			&symbol.Provider,
+			&symbol.Currency,
+			&symbol.Symbol,
+		); err != nil {
+			return nil, newScanErr(err)
+		}
+
+		symbols = append(symbols, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newRowsErr(err)
+	}
+
+	return symbols, nil
+}
+
+// SetProviderSymbol creates or replaces the symbol the provider knows the currency by.
+// Several currencies may share a symbol, as WETH and ETH both priced as ETH.
+func (p ProviderSymbol) SetProviderSymbol(ctx context.Context, symbol domain.ProviderSymbol) error {
+	var id int64
+
+	if err := p.db.QueryRowContext(ctx,
+		`INSERT INTO provider_symbols(provider, currency_id, symbol)
+		SELECT $1, id, $3 FROM currencies WHERE name=$2
+		ON CONFLICT (provider, currency_id) DO UPDATE SET symbol=EXCLUDED.symbol, created_at=CURRENT_TIMESTAMP
+		RETURNING currency_id`,
+		symbol.Provider,
+		symbol.Currency,
+		symbol.Symbol,
+	).Scan(
+		&id,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+		}
+
+		return newScanErr(err)
+	}
+
+	return nil
+}
+
+func (p ProviderSymbol) DeleteProviderSymbol(ctx context.Context, provider, name string) error {
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM provider_symbols s USING currencies c WHERE s.currency_id=c.id AND s.provider=$1 AND c.name=$2",
+		provider,
+		name,
+	)
+	if err != nil {
+		return newExecContextErr(err)
+	}
+
+	deletedRows, err := result.RowsAffected()
+	if err != nil {
+		return newUpdatedRowsErr(err)
+	}
+	if deletedRows == 0 {
+		return domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return nil
+}
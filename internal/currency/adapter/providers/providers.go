@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alemax1/currencies-api/config"
+	"github.com/alemax1/currencies-api/internal/currency/adapter/ecb"
+	filedrop "github.com/alemax1/currencies-api/internal/currency/adapter/fileDrop"
+	forex "github.com/alemax1/currencies-api/internal/currency/adapter/forexApi"
+	jsonapi "github.com/alemax1/currencies-api/internal/currency/adapter/jsonApi"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/alemax1/currencies-api/internal/currency/service"
+	"github.com/alemax1/currencies-api/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+// New builds the registry of the configured providers, each asking for the currencies by the symbols from the repo.
+func New(cfg config.Providers, symbols forex.SymbolRepo, logger logger.Logger) (*service.ProviderRegistry, error) {
+	aggregation, err := newAggregation(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := service.NewProviderRegistry(cfg.Default, aggregation, domain.QuotaPolicy{
+		LowWatermarkPercent: cfg.Quota.LowWatermarkPercent,
+		LowRefreshInterval:  cfg.Quota.LowRefreshInterval,
+		MinPriority:         int32(cfg.Quota.MinPriority),
+	})
+
+	for _, provider := range cfg.List {
+		api, err := newProvider(provider, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		api = forex.NewBudget(provider.Name, forex.NewSymbols(provider.Name, api, symbols, cfg.SymbolsRefresh))
+
+		types := make([]domain.CurrencyType, 0, len(provider.Types))
+		for _, tp := range provider.Types {
+			currencyType := domain.CurrencyType(strings.ToLower(tp))
+			if currencyType != domain.Fiat && currencyType != domain.Crypto {
+				return nil, fmt.Errorf("provider %s: unknown currency type %s", provider.Name, tp)
+			}
+
+			types = append(types, currencyType)
+		}
+
+		currencies := make([]string, 0, len(provider.Currencies))
+		for _, currency := range provider.Currencies {
+			currencies = append(currencies, strings.ToUpper(currency))
+		}
+
+		if err := registry.Register(provider.Name, api, service.ProviderOptions{
+			Types:      types,
+			Currencies: currencies,
+			Fallback:   provider.Fallback,
+			Budget: domain.QuotaBudget{
+				Daily:   int64(provider.API.DailyBudget),
+				Monthly: int64(provider.API.MonthlyBudget),
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := registry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+func newProvider(provider config.Provider, logger logger.Logger) (service.ForexAPI, error) {
+	switch provider.Kind {
+	case config.FastForexProvider:
+		return withBreaker(provider, forex.New(provider.Name, provider.API)), nil
+	case config.ECBProvider:
+		return withBreaker(provider, ecb.New(provider.Name, provider.API)), nil
+	case config.JSONProvider:
+		spec, err := jsonapi.NewSpec(provider.API, provider.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", provider.Name, err)
+		}
+
+		return withBreaker(provider, jsonapi.New(provider.Name, provider.API, spec)), nil
+	case config.FileProvider:
+		if provider.Dir == "" {
+			return nil, fmt.Errorf("provider %s: dir is required", provider.Name)
+		}
+
+		if provider.WatchInterval <= 0 {
+			return nil, fmt.Errorf("provider %s: invalid watch interval %s", provider.Name, provider.WatchInterval)
+		}
+
+		return withBreaker(provider, filedrop.New(provider.Name, provider.Dir, provider.WatchInterval, logger)), nil
+	default:
+		return nil, fmt.Errorf("provider %s: unknown kind %s", provider.Name, provider.Kind)
+	}
+}
+
+func newAggregation(cfg config.Providers) (service.Aggregation, error) {
+	switch cfg.Aggregation {
+	case config.FallbackAggregation:
+		return service.Aggregation{}, nil
+	case config.ConsensusAggregation:
+		tolerance, err := decimal.NewFromString(cfg.ConsensusTolerance)
+		if err != nil || tolerance.IsNegative() {
+			return service.Aggregation{}, fmt.Errorf("invalid consensus tolerance: %s", cfg.ConsensusTolerance)
+		}
+
+		return service.Aggregation{Consensus: true, Tolerance: tolerance}, nil
+	default:
+		return service.Aggregation{}, fmt.Errorf("unknown aggregation: %s", cfg.Aggregation)
+	}
+}
+
+func withBreaker(provider config.Provider, api forex.API) service.ForexAPI {
+	return forex.NewBreaker(provider.Name, api, provider.API.BreakerFailures, provider.API.BreakerOpenTimeout)
+}
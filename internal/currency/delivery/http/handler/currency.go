@@ -1,10 +1,9 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alemax1/currencies-api/internal/currency/domain"
 	"github.com/gofiber/fiber/v3"
@@ -12,11 +11,34 @@ import (
 )
 
 const (
-	fromQueryParam  = "from"
-	toQueryParam    = "to"
-	valueQueryParam = "value"
+	fromQueryParam     = "from"
+	toQueryParam       = "to"
+	valueQueryParam    = "value"
+	atQueryParam       = "at"
+	roundingQueryParam = "rounding"
+
+	nameParam            = "name"
+	quoteQueryParam      = "quote"
+	intervalQueryParam   = "interval"
+	currenciesQueryParam = "currencies"
+
+	maxMatrixCurrencies = 100
+
+	defaultCandlesQuote    = "USD"
+	defaultCandlesInterval = "1h"
+	defaultCandlesRange    = 24 * time.Hour
 )
 
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
 // CreateCurrency godoc
 //
 //	@Summary		add a new currency
@@ -36,21 +58,20 @@ func (h Handler) CreateCurrency(c fiber.Ctx) error {
 
 	upperName := strings.ToUpper(req.Name)
 
+	precision := domain.DefaultPrecision
+	if req.Precision != nil {
+		precision = *req.Precision
+	}
+
 	id, err := h.Currency.Create(c.Context(), domain.Currency{
-		Name: upperName,
-		Type: req.Type,
+		Name:      upperName,
+		Type:      req.Type,
+		Precision: precision,
 	})
 	if err != nil {
 		h.Logger.Error().Err(err).Msgf("create currency")
 
-		var serviceErr *domain.ServiceError
-		if errors.As(err, &serviceErr) {
-			if serviceErr.Type == domain.Client {
-				return c.Status(http.StatusBadRequest).JSON(errResponse{Error: serviceErr.Error()})
-			}
-		}
-
-		return c.Status(http.StatusInternalServerError).JSON(errResponse{Error: errSomethingWentWrong.Error()})
+		return serviceErrResponse(c, err)
 	}
 
 	return c.Status(http.StatusOK).JSON(createCurrencyResponse{ID: id})
@@ -64,7 +85,10 @@ func (h Handler) CreateCurrency(c fiber.Ctx) error {
 //	@Produce		json
 //	@Param			from	query		string	true	"currency from"
 //	@Param			to		query		string	true	"currency to"
-//	@Param			value	query		float64	true	"currency from value"
+//	@Param			value	query		string	true	"currency from value as a decimal string"
+//	@Param			at			query		string	false	"point in time (RFC3339) whose rates are used"
+//	@Param			rounding	query		string	false	"rounding mode: half_even, half_up, floor, ceiling"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
 //	@Success		200		{object}	getRateResponse
 //	@Failure		400		{object}	errResponse
 //	@Failure		500		{object}	errResponse
@@ -78,37 +102,131 @@ func (h Handler) GetRate(c fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
 	}
 
-	floatValue, err := strconv.ParseFloat(value, 64)
+	decimalValue, err := decimal.NewFromString(value)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if !decimalValue.IsPositive() {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	at, err := parseAt(c.Query(atQueryParam))
 	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
 	}
 
-	if floatValue <= 0 {
+	rounding := domain.RoundingMode(c.Query(roundingQueryParam))
+	if rounding != "" && !rounding.IsValid() {
 		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
 	}
 
 	fromValue = strings.ToUpper(fromValue)
 	toValue = strings.ToUpper(toValue)
 
-	rate, err := h.Currency.GetRate(c.Context(), domain.Rate{
-		From:  fromValue,
-		To:    toValue,
-		Value: decimal.NewFromFloat(floatValue),
+	conversion, err := h.Currency.GetRate(c.Context(), domain.Rate{
+		From:     fromValue,
+		To:       toValue,
+		Value:    decimalValue,
+		At:       at,
+		Rounding: rounding,
 	})
 	if err != nil {
 		h.Logger.Error().Err(err).Msgf("get currency rate")
 
-		var serviceErr *domain.ServiceError
-		if errors.As(err, &serviceErr) {
-			if serviceErr.Type == domain.Client {
-				return c.Status(http.StatusBadRequest).JSON(errResponse{Error: serviceErr.Error()})
-			}
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(conversionToDto(decimalFormatOf(c), conversion))
+}
+
+// GetRates godoc
+//
+//	@Summary		get currencies rates in batch
+//	@Description	convert many values at once, every item gets its own result or error
+//	@Tags			currency
+//	@Accept			json
+//	@Produce		json
+//	@Param			batch	body		getRatesRequest	true	"items to convert"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200		{object}	getRatesResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/rate/batch [post]
+func (h Handler) GetRates(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[getRatesRequest](c.Request().Body())
+	if err != nil || len(req.Items) > h.Cfg.BatchMaxItems {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	at, err := parseAt(req.At)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	rounding := domain.RoundingMode(req.Rounding)
+	if rounding != "" && !rounding.IsValid() {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]rateResult, len(req.Items))
+	rates := make([]domain.Rate, 0, len(req.Items))
+	positions := make([]int, 0, len(req.Items))
+
+	for i, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			resp[i] = rateResult{Error: errInvalidInput.Error()}
+			continue
+		}
+
+		rates = append(rates, domain.Rate{
+			From:     strings.ToUpper(item.From),
+			To:       strings.ToUpper(item.To),
+			Value:    item.Value,
+			At:       at,
+			Rounding: rounding,
+		})
+		positions = append(positions, i)
+	}
+
+	results, err := h.Currency.GetRates(c.Context(), rates)
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get currency rates")
+
+		return serviceErrResponse(c, err)
+	}
+
+	for i, result := range results {
+		if result.Err != nil {
+			resp[positions[i]] = rateResult{Error: serviceErrMessage(result.Err)}
+			continue
 		}
 
-		return c.Status(http.StatusInternalServerError).JSON(errResponse{Error: errSomethingWentWrong.Error()})
+		conversion := conversionToDto(format, result.Conversion)
+		resp[positions[i]] = rateResult{getRateResponse: &conversion}
 	}
 
-	return c.Status(http.StatusOK).JSON(getRateResponse{Rate: rate})
+	return c.Status(http.StatusOK).JSON(getRatesResponse{Results: resp})
+}
+
+// parseAt parses an optional RFC3339 instant that must not be in the future.
+func parseAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if at.After(time.Now()) {
+		return time.Time{}, errInvalidInput
+	}
+
+	return at, nil
 }
 
 // ChangeCurrencyAvailability godoc
@@ -133,14 +251,34 @@ func (h Handler) ChangeCurrencyAvailability(c fiber.Ctx) error {
 	if err := h.Currency.ChangeAvailability(c.Context(), upperName, req.IsAvailable); err != nil {
 		h.Logger.Error().Err(err).Msgf("change currency availability")
 
-		var serviceErr *domain.ServiceError
-		if errors.As(err, &serviceErr) {
-			if serviceErr.Type == domain.Client {
-				return c.Status(http.StatusBadRequest).JSON(errResponse{Error: serviceErr.Error()})
-			}
-		}
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+// ChangeCurrencyPriority godoc
+//
+//	@Summary		change currency priority
+//	@Description	change which currencies are still refreshed when a provider budget runs low, higher goes first
+//	@Tags			currency
+//	@Accept			json
+//	@Produce		json
+//	@Param			priority	body		changeCurrencyPriorityRequest	true	"priority"
+//	@Success		200			{object}	defaultResponse
+//	@Failure		400			{object}	errResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/priority [patch]
+func (h Handler) ChangeCurrencyPriority(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[changeCurrencyPriorityRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.ChangePriority(c.Context(), strings.ToUpper(req.Name), req.Priority); err != nil {
+		h.Logger.Error().Err(err).Msgf("change currency priority")
 
-		return c.Status(http.StatusInternalServerError).JSON(errResponse{Error: errSomethingWentWrong.Error()})
+		return serviceErrResponse(c, err)
 	}
 
 	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
@@ -152,6 +290,7 @@ func (h Handler) ChangeCurrencyAvailability(c fiber.Ctx) error {
 //	@Description	get currencies
 //	@Tags			currency
 //	@Produce		json
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
 //	@Success		200	{object}	getAvailableCurrenciesResponse
 //	@Failure		400	{object}	errResponse
 //	@Failure		500	{object}	errResponse
@@ -161,21 +300,146 @@ func (h Handler) GeteCurrencies(c fiber.Ctx) error {
 	if err != nil {
 		h.Logger.Error().Err(err).Msgf("get available currencies")
 
-		var serviceErr *domain.ServiceError
-		if errors.As(err, &serviceErr) {
-			if serviceErr.Type == domain.Client {
-				return c.Status(http.StatusBadRequest).JSON(errResponse{Error: serviceErr.Error()})
-			}
-		}
-
-		return c.Status(http.StatusInternalServerError).JSON(errResponse{Error: errSomethingWentWrong.Error()})
+		return serviceErrResponse(c, err)
 	}
 
+	format := decimalFormatOf(c)
+
 	resp := make([]currency, 0, len(currencies))
 
 	for i := range currencies {
-		resp = append(resp, currencyToDto(currencies[i]))
+		resp = append(resp, currencyToDto(format, currencies[i]))
 	}
 
 	return c.Status(http.StatusOK).JSON(getAvailableCurrenciesResponse{Currencies: resp})
 }
+
+// GetCandles godoc
+//
+//	@Summary		get currency candles
+//	@Description	get OHLC candles of the currency price built from rate history
+//	@Tags			currency
+//	@Produce		json
+//	@Param			name		path		string	true	"currency name"
+//	@Param			quote		query		string	false	"quote currency, USD by default"
+//	@Param			interval	query		string	false	"candle interval: 1m, 5m, 15m, 30m, 1h, 4h, 1d"
+//	@Param			from		query		string	false	"range start (RFC3339), 24h before range end by default"
+//	@Param			to			query		string	false	"range end (RFC3339), now by default"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200			{object}	getCandlesResponse
+//	@Failure		400			{object}	errResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/{name}/candles [get]
+func (h Handler) GetCandles(c fiber.Ctx) error {
+	name := strings.ToUpper(c.Params(nameParam))
+	quote := strings.ToUpper(c.Query(quoteQueryParam, defaultCandlesQuote))
+	intervalValue := c.Query(intervalQueryParam, defaultCandlesInterval)
+
+	interval, ok := candleIntervals[intervalValue]
+	if !ok {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	to := time.Now()
+	if toValue := c.Query(toQueryParam); toValue != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, toValue); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+		}
+	}
+
+	from := to.Add(-defaultCandlesRange)
+	if fromValue := c.Query(fromQueryParam); fromValue != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, fromValue); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+		}
+	}
+
+	if !from.Before(to) {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	candles, err := h.Currency.GetCandles(c.Context(), domain.CandlesRequest{
+		Name:     name,
+		Quote:    quote,
+		Interval: interval,
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get currency candles")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]candle, 0, len(candles))
+
+	for i := range candles {
+		resp = append(resp, candleToDto(format, candles[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getCandlesResponse{
+		Name:     name,
+		Quote:    quote,
+		Interval: intervalValue,
+		Candles:  resp,
+	})
+}
+
+// GetMatrix godoc
+//
+//	@Summary		get conversion matrix
+//	@Description	get cross rates of every allowed ordered pair among the currencies
+//	@Tags			currency
+//	@Produce		json
+//	@Param			currencies	query		string	false	"comma separated currency names, all currencies by default"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200			{object}	getMatrixResponse
+//	@Failure		400			{object}	errResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/matrix [get]
+func (h Handler) GetMatrix(c fiber.Ctx) error {
+	var names []string
+
+	if value := c.Query(currenciesQueryParam); value != "" {
+		seen := make(map[string]bool)
+
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToUpper(strings.TrimSpace(name))
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			names = append(names, name)
+		}
+
+		if len(names) == 0 || len(names) > maxMatrixCurrencies {
+			return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+		}
+	}
+
+	rates, err := h.Currency.GetMatrix(c.Context(), names)
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get conversion matrix")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make(map[string]map[string]jsonDecimal)
+
+	for _, rate := range rates {
+		if _, ok := resp[rate.From]; !ok {
+			resp[rate.From] = make(map[string]jsonDecimal)
+		}
+
+		resp[rate.From][rate.To] = format.decimal(rate.Rate)
+	}
+
+	return c.Status(http.StatusOK).JSON(getMatrixResponse{Rates: resp})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/shopspring/decimal"
+)
+
+// decimalsQueryParam set to numberDecimals makes a response carry its amounts and rates
+// as JSON numbers instead of decimal strings, for clients that cannot parse the latter.
+const (
+	decimalsQueryParam = "decimals"
+	numberDecimals     = "number"
+)
+
+// jsonDecimal is a decimal of a response, sent as a decimal string keeping every digit
+// unless the request asked for JSON numbers.
+type jsonDecimal struct {
+	decimal.Decimal
+	asNumber bool
+}
+
+func (d jsonDecimal) MarshalJSON() ([]byte, error) {
+	if d.asNumber {
+		return []byte(d.Decimal.String()), nil
+	}
+
+	return []byte(strconv.Quote(d.Decimal.String())), nil
+}
+
+// decimalFormat is how the request wants the decimals of its response, the DTO builders
+// take it to build every jsonDecimal.
+type decimalFormat struct {
+	asNumber bool
+}
+
+func decimalFormatOf(c fiber.Ctx) decimalFormat {
+	return decimalFormat{asNumber: c.Query(decimalsQueryParam) == numberDecimals}
+}
+
+func (f decimalFormat) decimal(d decimal.Decimal) jsonDecimal {
+	return jsonDecimal{Decimal: d, asNumber: f.asNumber}
+}
+
+// nullDecimal returns nil for a null decimal so the field can be omitted.
+func (f decimalFormat) nullDecimal(d decimal.NullDecimal) *jsonDecimal {
+	if !d.Valid {
+		return nil
+	}
+
+	value := f.decimal(d.Decimal)
+
+	return &value
+}
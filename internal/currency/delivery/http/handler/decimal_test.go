@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+	"github.com/shopspring/decimal"
+)
+
+func TestDecimalFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name: "strings by default",
+			want: `{"confidence":"0.5","rates":{"EUR":{"USD":"1.00000000000000000001"}},` +
+				`"tiers":[{"fromAmount":"100","fixed":"0.25","percentage":"0.01"}],` +
+				`"results":[{"rate":"2","gross":"2","fees":{"fixed":"0","percentage":"0","capAdjustment":"0","total":"0"},"net":"2",` +
+				`"precision":0,"rounding":"","mid":"0","bid":"0","ask":"0","markup":"0"}]}`,
+		},
+		{
+			name:  "numbers on request",
+			query: "?decimals=number",
+			want: `{"confidence":0.5,"rates":{"EUR":{"USD":1.00000000000000000001}},` +
+				`"tiers":[{"fromAmount":100,"fixed":0.25,"percentage":0.01}],` +
+				`"results":[{"rate":2,"gross":2,"fees":{"fixed":0,"percentage":0,"capAdjustment":0,"total":0},"net":2,` +
+				`"precision":0,"rounding":"","mid":0,"bid":0,"ask":0,"markup":0}]}`,
+		},
+	}
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		format := decimalFormatOf(c)
+		schedule := feeScheduleToDto(format, domain.FeeSchedule{
+			Tiers: []domain.FeeTier{{
+				FromAmount: decimal.NewFromInt(100),
+				Fixed:      decimal.RequireFromString("0.25"),
+				Percentage: decimal.RequireFromString("0.01"),
+			}},
+		})
+		conversion := conversionToDto(format, domain.Conversion{Amount: decimal.NewFromInt(2), Net: decimal.NewFromInt(2)})
+
+		return c.Status(http.StatusOK).JSON(struct {
+			Confidence *jsonDecimal                      `json:"confidence"`
+			Rates      map[string]map[string]jsonDecimal `json:"rates"`
+			Tiers      []feeScheduleTier                 `json:"tiers"`
+			Results    []rateResult                      `json:"results"`
+		}{
+			Confidence: format.nullDecimal(decimal.NewNullDecimal(decimal.RequireFromString("0.5"))),
+			Rates: map[string]map[string]jsonDecimal{
+				"EUR": {"USD": format.decimal(decimal.RequireFromString("1.00000000000000000001"))},
+			},
+			Tiers:   schedule.Tiers,
+			Results: []rateResult{{getRateResponse: &conversion}},
+		})
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := app.Test(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if !json.Valid(body) {
+				t.Fatalf("invalid JSON: %s", body)
+			}
+
+			if string(body) != tt.want {
+				t.Errorf("got %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullDecimal(t *testing.T) {
+	if got := (decimalFormat{}).nullDecimal(decimal.NullDecimal{}); got != nil {
+		t.Errorf("got %s for a null decimal, want nil", got)
+	}
+}
@@ -1,9 +1,38 @@
 package handler
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+)
 
 var (
 	errInvalidJSONBodyRequest = errors.New("invalid JSON body request")
 	errInvalidInput           = errors.New("invalid input")
 	errSomethingWentWrong     = errors.New("something went wrong")
 )
+
+// serviceErrMessage returns the message of a client error or hides the details of an internal one.
+func serviceErrMessage(err error) string {
+	var serviceErr *domain.ServiceError
+	if errors.As(err, &serviceErr) {
+		if serviceErr.Type == domain.Client {
+			return serviceErr.Error()
+		}
+	}
+
+	return errSomethingWentWrong.Error()
+}
+
+func serviceErrResponse(c fiber.Ctx, err error) error {
+	var serviceErr *domain.ServiceError
+	if errors.As(err, &serviceErr) {
+		if serviceErr.Type == domain.Client {
+			return c.Status(http.StatusBadRequest).JSON(errResponse{Error: serviceErr.Error()})
+		}
+	}
+
+	return c.Status(http.StatusInternalServerError).JSON(errResponse{Error: errSomethingWentWrong.Error()})
+}
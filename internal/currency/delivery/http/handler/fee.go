@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+	"github.com/shopspring/decimal"
+)
+
+// GetFeeSchedules godoc
+//
+//	@Summary		get fee schedules
+//	@Description	get fees charged on conversions
+//	@Tags			fees
+//	@Produce		json
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200	{object}	getFeeSchedulesResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/fees [get]
+func (h Handler) GetFeeSchedules(c fiber.Ctx) error {
+	schedules, err := h.Currency.GetFeeSchedules(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get fee schedules")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]feeSchedule, 0, len(schedules))
+
+	for i := range schedules {
+		resp = append(resp, feeScheduleToDto(format, schedules[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getFeeSchedulesResponse{Schedules: resp})
+}
+
+// SaveFeeSchedule godoc
+//
+//	@Summary		save fee schedule
+//	@Description	create or replace the fixed, percentage and tiered fees of a pair
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			schedule	body		feeScheduleRequest	true	"fee schedule"
+//	@Success		200			{object}	createCurrencyResponse
+//	@Failure		400			{object}	errResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/fees [post]
+func (h Handler) SaveFeeSchedule(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[feeScheduleRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	schedule := domain.FeeSchedule{
+		From:       strings.ToUpper(req.From),
+		To:         strings.ToUpper(req.To),
+		Fixed:      req.Fixed,
+		Percentage: req.Percentage,
+		MinFee:     toNullDecimal(req.MinFee),
+		MaxFee:     toNullDecimal(req.MaxFee),
+	}
+
+	for _, tier := range req.Tiers {
+		schedule.Tiers = append(schedule.Tiers, domain.FeeTier{
+			FromAmount: tier.FromAmount,
+			Fixed:      tier.Fixed,
+			Percentage: tier.Percentage,
+		})
+	}
+
+	id, err := h.Currency.SaveFeeSchedule(c.Context(), schedule)
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("save fee schedule")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(createCurrencyResponse{ID: id})
+}
+
+// DeleteFeeSchedule godoc
+//
+//	@Summary		delete fee schedule
+//	@Description	delete fee schedule by id
+//	@Tags			fees
+//	@Produce		json
+//	@Param			id	path		int	true	"schedule id"
+//	@Success		200	{object}	defaultResponse
+//	@Failure		400	{object}	errResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/fees/{id} [delete]
+func (h Handler) DeleteFeeSchedule(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params(idParam), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.DeleteFeeSchedule(c.Context(), id); err != nil {
+		h.Logger.Error().Err(err).Msgf("delete fee schedule")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+func toNullDecimal(d *decimal.Decimal) decimal.NullDecimal {
+	if d == nil {
+		return decimal.NullDecimal{}
+	}
+
+	return decimal.NewNullDecimal(*d)
+}
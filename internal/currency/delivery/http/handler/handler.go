@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"github.com/alemax1/currencies-api/config"
 	"github.com/alemax1/currencies-api/internal/currency/service"
 	"github.com/alemax1/currencies-api/pkg/logger"
 	"github.com/gofiber/fiber/v3"
@@ -8,15 +9,18 @@ import (
 
 type Handler struct {
 	*service.Service
+	Cfg    config.Handler
 	Logger logger.Logger
 }
 
 func New(
 	service *service.Service,
+	cfg config.Handler,
 	l logger.Logger,
 ) *Handler {
 	return &Handler{
 		Service: service,
+		Cfg:     cfg,
 		Logger:  l,
 	}
 }
@@ -28,6 +32,41 @@ func (h Handler) InitRoutes(app *fiber.App) {
 
 	currencyApi.Post("", h.CreateCurrency)
 	currencyApi.Get("/rate", h.GetRate)
+	currencyApi.Post("/rate/batch", h.GetRates)
 	currencyApi.Patch("/availability", h.ChangeCurrencyAvailability)
+	currencyApi.Patch("/priority", h.ChangeCurrencyPriority)
 	currencyApi.Get("/all", h.GeteCurrencies)
+	currencyApi.Get("/matrix", h.GetMatrix)
+	currencyApi.Get("/:name/candles", h.GetCandles)
+
+	currencyApi.Post("/quote", h.CreateQuote)
+	currencyApi.Post("/quote/:id/accept", h.AcceptQuote)
+
+	currencyApi.Get("/pairs", h.GetPairPolicies)
+	currencyApi.Post("/pairs", h.SavePairPolicy)
+	currencyApi.Delete("/pairs/:id", h.DeletePairPolicy)
+
+	currencyApi.Get("/spreads", h.GetSpreads)
+	currencyApi.Post("/spreads", h.SaveSpread)
+	currencyApi.Delete("/spreads/:id", h.DeleteSpread)
+
+	currencyApi.Get("/fees", h.GetFeeSchedules)
+	currencyApi.Post("/fees", h.SaveFeeSchedule)
+	currencyApi.Delete("/fees/:id", h.DeleteFeeSchedule)
+
+	currencyApi.Get("/overrides", h.GetOverrides)
+	currencyApi.Post("/overrides", h.SetOverride)
+	currencyApi.Delete("/overrides/:name", h.ClearOverride)
+	currencyApi.Get("/overrides/:name/audit", h.GetOverrideAudit)
+
+	currencyApi.Get("/pegs", h.GetPegs)
+	currencyApi.Post("/pegs", h.SetPeg)
+	currencyApi.Delete("/pegs/:name", h.ClearPeg)
+	currencyApi.Get("/pegs/:name/events", h.GetDepegEvents)
+
+	currencyApi.Get("/providers/health", h.GetProviderHealth)
+	currencyApi.Get("/providers/quota", h.GetProviderQuota)
+	currencyApi.Get("/providers/symbols", h.GetProviderSymbols)
+	currencyApi.Post("/providers/symbols", h.SetProviderSymbol)
+	currencyApi.Delete("/providers/symbols/:provider/:name", h.DeleteProviderSymbol)
 }
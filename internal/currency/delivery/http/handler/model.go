@@ -2,9 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/alemax1/currencies-api/internal/currency/domain"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/shopspring/decimal"
 )
 
 func ParseAndValidateRequest[T interface{ Validate() error }](body []byte) (T, error) {
@@ -17,14 +19,16 @@ func ParseAndValidateRequest[T interface{ Validate() error }](body []byte) (T, e
 }
 
 type currencyCreateRequest struct {
-	Name string              `json:"name"`
-	Type domain.CurrencyType `json:"type"`
+	Name      string              `json:"name"`
+	Type      domain.CurrencyType `json:"type"`
+	Precision *int32              `json:"precision"`
 }
 
 func (r currencyCreateRequest) Validate() error {
 	if err := validation.ValidateStruct(&r,
 		validation.Field(&r.Name, validation.Required, validation.Length(2, 255)),
 		validation.Field(&r.Type, validation.In(domain.Crypto, domain.Fiat)),
+		validation.Field(&r.Precision, validation.Min(int32(0)), validation.Max(int32(30))),
 	); err != nil {
 		return errInvalidInput
 	}
@@ -47,6 +51,45 @@ func (r changeCurrencyAvailabilityRequest) Validate() error {
 	return nil
 }
 
+type changeCurrencyPriorityRequest struct {
+	Name     string `json:"name"`
+	Priority int32  `json:"priority"`
+}
+
+func (r changeCurrencyPriorityRequest) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(2, 255)),
+	); err != nil {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+type pairPolicyRequest struct {
+	From      string              `json:"from"`
+	To        string              `json:"to"`
+	FromType  domain.CurrencyType `json:"fromType"`
+	ToType    domain.CurrencyType `json:"toType"`
+	IsAllowed bool                `json:"isAllowed"`
+}
+
+// Validate accepts either a pair of currency names or a pair of currency types.
+func (r pairPolicyRequest) Validate() error {
+	byName := r.FromType == "" && r.ToType == ""
+
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.From, validation.When(byName, validation.Required, validation.Length(2, 255)).Else(validation.Empty)),
+		validation.Field(&r.To, validation.When(byName, validation.Required, validation.Length(2, 255)).Else(validation.Empty)),
+		validation.Field(&r.FromType, validation.When(!byName, validation.Required, validation.In(domain.Crypto, domain.Fiat))),
+		validation.Field(&r.ToType, validation.When(!byName, validation.Required, validation.In(domain.Crypto, domain.Fiat))),
+	); err != nil {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
 type createCurrencyResponse struct {
 	ID int64 `json:"id"`
 }
@@ -55,8 +98,91 @@ type defaultResponse struct {
 	Success bool `json:"success"`
 }
 
+type fee struct {
+	Fixed         jsonDecimal `json:"fixed"`
+	Percentage    jsonDecimal `json:"percentage"`
+	CapAdjustment jsonDecimal `json:"capAdjustment"`
+	Total         jsonDecimal `json:"total"`
+}
+
+func feeToDto(format decimalFormat, f domain.Fee) fee {
+	return fee{
+		Fixed:         format.decimal(f.Fixed),
+		Percentage:    format.decimal(f.Percentage),
+		CapAdjustment: format.decimal(f.CapAdjustment),
+		Total:         format.decimal(f.Total),
+	}
+}
+
+// getRateResponse keeps rate as the gross amount for older clients.
 type getRateResponse struct {
-	Rate float64 `json:"rate"`
+	Rate      jsonDecimal `json:"rate"`
+	Gross     jsonDecimal `json:"gross"`
+	Fees      fee         `json:"fees"`
+	Net       jsonDecimal `json:"net"`
+	Precision int32       `json:"precision"`
+	Rounding  string      `json:"rounding"`
+	Mid       jsonDecimal `json:"mid"`
+	Bid       jsonDecimal `json:"bid"`
+	Ask       jsonDecimal `json:"ask"`
+	Markup    jsonDecimal `json:"markup"`
+}
+
+func conversionToDto(format decimalFormat, c domain.Conversion) getRateResponse {
+	return getRateResponse{
+		Rate:      format.decimal(c.Amount),
+		Gross:     format.decimal(c.Amount),
+		Fees:      feeToDto(format, c.Fee),
+		Net:       format.decimal(c.Net),
+		Precision: c.Precision,
+		Rounding:  string(c.Rounding),
+		Mid:       format.decimal(c.Mid),
+		Bid:       format.decimal(c.Bid),
+		Ask:       format.decimal(c.Ask),
+		Markup:    format.decimal(c.Markup),
+	}
+}
+
+type rateItem struct {
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Value decimal.Decimal `json:"value"`
+}
+
+func (r rateItem) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.From, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.To, validation.Required, validation.Length(2, 255)),
+	); err != nil || !r.Value.IsPositive() {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+type getRatesRequest struct {
+	Items    []rateItem `json:"items"`
+	At       string     `json:"at"`
+	Rounding string     `json:"rounding"`
+}
+
+// Validate checks only the envelope, invalid items are reported per item.
+func (r getRatesRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+// rateResult holds either the conversion or the error of a batch item.
+type rateResult struct {
+	*getRateResponse
+	Error string `json:"err,omitempty"`
+}
+
+type getRatesResponse struct {
+	Results []rateResult `json:"results"`
 }
 
 type errResponse struct {
@@ -64,23 +190,594 @@ type errResponse struct {
 }
 
 type currency struct {
-	ID          int64   `json:"id"`
-	Name        string  `json:"name"`
-	Type        string  `json:"type"`
-	ValueUSD    float64 `json:"valueUSD"`
-	IsAvailable bool    `json:"isAvailable"`
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`
+	ValueUSD    jsonDecimal  `json:"valueUSD"`
+	IsAvailable bool         `json:"isAvailable"`
+	Precision   int32        `json:"precision"`
+	Provider    string       `json:"provider,omitempty"`
+	Confidence  *jsonDecimal `json:"confidence,omitempty"`
+	Priority    int32        `json:"priority"`
 }
 
 type getAvailableCurrenciesResponse struct {
 	Currencies []currency `json:"currencies"`
 }
 
-func currencyToDto(curr domain.Currency) currency {
-	return currency{
+func currencyToDto(format decimalFormat, curr domain.Currency) currency {
+	resp := currency{
 		ID:          curr.ID,
 		Name:        curr.Name,
 		Type:        string(curr.Type),
-		ValueUSD:    curr.ValueUSD.InexactFloat64(),
+		ValueUSD:    format.decimal(curr.ValueUSD),
 		IsAvailable: curr.IsAvailable,
+		Precision:   curr.Precision,
+		Provider:    curr.Provider,
+		Priority:    curr.Priority,
+		Confidence:  format.nullDecimal(curr.Confidence),
+	}
+
+	return resp
+}
+
+type candle struct {
+	Start time.Time   `json:"start"`
+	Open  jsonDecimal `json:"open"`
+	High  jsonDecimal `json:"high"`
+	Low   jsonDecimal `json:"low"`
+	Close jsonDecimal `json:"close"`
+}
+
+type getCandlesResponse struct {
+	Name     string   `json:"name"`
+	Quote    string   `json:"quote"`
+	Interval string   `json:"interval"`
+	Candles  []candle `json:"candles"`
+}
+
+func candleToDto(format decimalFormat, c domain.Candle) candle {
+	return candle{
+		Start: c.Start,
+		Open:  format.decimal(c.Open),
+		High:  format.decimal(c.High),
+		Low:   format.decimal(c.Low),
+		Close: format.decimal(c.Close),
 	}
 }
+
+type pairPolicy struct {
+	ID        int64  `json:"id"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	FromType  string `json:"fromType,omitempty"`
+	ToType    string `json:"toType,omitempty"`
+	IsAllowed bool   `json:"isAllowed"`
+}
+
+type getPairPoliciesResponse struct {
+	Policies []pairPolicy `json:"policies"`
+}
+
+func pairPolicyToDto(p domain.PairPolicy) pairPolicy {
+	return pairPolicy{
+		ID:        p.ID,
+		From:      p.From,
+		To:        p.To,
+		FromType:  string(p.FromType),
+		ToType:    string(p.ToType),
+		IsAllowed: p.IsAllowed,
+	}
+}
+
+// getMatrixResponse maps a source currency to the cross rates into every allowed target currency.
+type getMatrixResponse struct {
+	Rates map[string]map[string]jsonDecimal `json:"rates"`
+}
+
+type createQuoteRequest struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Value    decimal.Decimal `json:"value"`
+	Rounding string          `json:"rounding"`
+}
+
+func (r createQuoteRequest) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.From, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.To, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.Rounding, validation.In(
+			string(domain.HalfEven), string(domain.HalfUp), string(domain.Floor), string(domain.Ceiling),
+		)),
+	); err != nil || !r.Value.IsPositive() {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+// quoteResponse keeps fee as the fee total for older clients, fees holds its lines.
+type quoteResponse struct {
+	ID         string      `json:"id"`
+	From       string      `json:"from"`
+	To         string      `json:"to"`
+	Value      jsonDecimal `json:"value"`
+	Rate       jsonDecimal `json:"rate"`
+	Fee        jsonDecimal `json:"fee"`
+	Fees       fee         `json:"fees"`
+	Net        jsonDecimal `json:"net"`
+	Precision  int32       `json:"precision"`
+	Rounding   string      `json:"rounding"`
+	Mid        jsonDecimal `json:"mid"`
+	Bid        jsonDecimal `json:"bid"`
+	Ask        jsonDecimal `json:"ask"`
+	Markup     jsonDecimal `json:"markup"`
+	ExpiresAt  time.Time   `json:"expiresAt"`
+	AcceptedAt *time.Time  `json:"acceptedAt,omitempty"`
+}
+
+func quoteToDto(format decimalFormat, q domain.Quote) quoteResponse {
+	resp := quoteResponse{
+		ID:        q.ID,
+		From:      q.From,
+		To:        q.To,
+		Value:     format.decimal(q.Value),
+		Rate:      format.decimal(q.Conversion.Amount),
+		Fee:       format.decimal(q.Conversion.Fee.Total),
+		Fees:      feeToDto(format, q.Conversion.Fee),
+		Net:       format.decimal(q.Conversion.Net),
+		Precision: q.Conversion.Precision,
+		Rounding:  string(q.Conversion.Rounding),
+		Mid:       format.decimal(q.Conversion.Mid),
+		Bid:       format.decimal(q.Conversion.Bid),
+		Ask:       format.decimal(q.Conversion.Ask),
+		Markup:    format.decimal(q.Conversion.Markup),
+		ExpiresAt: q.ExpiresAt,
+	}
+
+	if !q.AcceptedAt.IsZero() {
+		resp.AcceptedAt = &q.AcceptedAt
+	}
+
+	return resp
+}
+
+type spreadRequest struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Currency string              `json:"currency"`
+	FromType domain.CurrencyType `json:"fromType"`
+	ToType   domain.CurrencyType `json:"toType"`
+	Markup   decimal.Decimal     `json:"markup"`
+}
+
+// Validate accepts exactly one target: a pair of names, a currency name or a pair of types.
+func (r spreadRequest) Validate() error {
+	byPair := r.From != "" || r.To != ""
+	byCurrency := r.Currency != ""
+	byType := r.FromType != "" || r.ToType != ""
+
+	targets := 0
+	for _, ok := range []bool{byPair, byCurrency, byType} {
+		if ok {
+			targets++
+		}
+	}
+
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.From, validation.When(byPair, validation.Required, validation.Length(2, 255))),
+		validation.Field(&r.To, validation.When(byPair, validation.Required, validation.Length(2, 255))),
+		validation.Field(&r.Currency, validation.Length(2, 255)),
+		validation.Field(&r.FromType, validation.When(byType, validation.Required, validation.In(domain.Crypto, domain.Fiat))),
+		validation.Field(&r.ToType, validation.When(byType, validation.Required, validation.In(domain.Crypto, domain.Fiat))),
+	); err != nil || targets != 1 {
+		return errInvalidInput
+	}
+
+	if r.Markup.IsNegative() || r.Markup.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+type spread struct {
+	ID       int64       `json:"id"`
+	From     string      `json:"from,omitempty"`
+	To       string      `json:"to,omitempty"`
+	Currency string      `json:"currency,omitempty"`
+	FromType string      `json:"fromType,omitempty"`
+	ToType   string      `json:"toType,omitempty"`
+	Markup   jsonDecimal `json:"markup"`
+}
+
+type getSpreadsResponse struct {
+	Spreads []spread `json:"spreads"`
+}
+
+func spreadToDto(format decimalFormat, s domain.Spread) spread {
+	return spread{
+		ID:       s.ID,
+		From:     s.From,
+		To:       s.To,
+		Currency: s.Currency,
+		FromType: string(s.FromType),
+		ToType:   string(s.ToType),
+		Markup:   format.decimal(s.Markup),
+	}
+}
+
+type feeTier struct {
+	FromAmount decimal.Decimal `json:"fromAmount"`
+	Fixed      decimal.Decimal `json:"fixed"`
+	Percentage decimal.Decimal `json:"percentage"`
+}
+
+type feeScheduleRequest struct {
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Fixed      decimal.Decimal  `json:"fixed"`
+	Percentage decimal.Decimal  `json:"percentage"`
+	MinFee     *decimal.Decimal `json:"minFee"`
+	MaxFee     *decimal.Decimal `json:"maxFee"`
+	Tiers      []feeTier        `json:"tiers"`
+}
+
+func (r feeScheduleRequest) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.From, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.To, validation.Required, validation.Length(2, 255)),
+	); err != nil {
+		return errInvalidInput
+	}
+
+	if !isValidFee(r.Fixed, r.Percentage) {
+		return errInvalidInput
+	}
+
+	for _, tier := range r.Tiers {
+		if tier.FromAmount.IsNegative() || !isValidFee(tier.Fixed, tier.Percentage) {
+			return errInvalidInput
+		}
+	}
+
+	if (r.MinFee != nil && r.MinFee.IsNegative()) || (r.MaxFee != nil && r.MaxFee.IsNegative()) {
+		return errInvalidInput
+	}
+
+	if r.MinFee != nil && r.MaxFee != nil && r.MinFee.GreaterThan(*r.MaxFee) {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+func isValidFee(fixed, percentage decimal.Decimal) bool {
+	return !fixed.IsNegative() && !percentage.IsNegative() && percentage.LessThan(decimal.NewFromInt(1))
+}
+
+type feeSchedule struct {
+	ID         int64             `json:"id"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Fixed      jsonDecimal       `json:"fixed"`
+	Percentage jsonDecimal       `json:"percentage"`
+	MinFee     *jsonDecimal      `json:"minFee,omitempty"`
+	MaxFee     *jsonDecimal      `json:"maxFee,omitempty"`
+	Tiers      []feeScheduleTier `json:"tiers"`
+}
+
+type feeScheduleTier struct {
+	FromAmount jsonDecimal `json:"fromAmount"`
+	Fixed      jsonDecimal `json:"fixed"`
+	Percentage jsonDecimal `json:"percentage"`
+}
+
+type getFeeSchedulesResponse struct {
+	Schedules []feeSchedule `json:"schedules"`
+}
+
+func feeScheduleToDto(format decimalFormat, s domain.FeeSchedule) feeSchedule {
+	resp := feeSchedule{
+		ID:         s.ID,
+		From:       s.From,
+		To:         s.To,
+		Fixed:      format.decimal(s.Fixed),
+		Percentage: format.decimal(s.Percentage),
+		MinFee:     format.nullDecimal(s.MinFee),
+		MaxFee:     format.nullDecimal(s.MaxFee),
+		Tiers:      make([]feeScheduleTier, 0, len(s.Tiers)),
+	}
+
+	for _, tier := range s.Tiers {
+		resp.Tiers = append(resp.Tiers, feeScheduleTier{
+			FromAmount: format.decimal(tier.FromAmount),
+			Fixed:      format.decimal(tier.Fixed),
+			Percentage: format.decimal(tier.Percentage),
+		})
+	}
+
+	return resp
+}
+
+type setOverrideRequest struct {
+	Name      string          `json:"name"`
+	ValueUSD  decimal.Decimal `json:"valueUSD"`
+	ExpiresAt *time.Time      `json:"expiresAt"`
+	Reason    string          `json:"reason"`
+	Actor     string          `json:"actor"`
+}
+
+func (r setOverrideRequest) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.Reason, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Actor, validation.Required, validation.Length(1, 255)),
+	); err != nil {
+		return errInvalidInput
+	}
+
+	if !r.ValueUSD.IsPositive() {
+		return errInvalidInput
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+type override struct {
+	Name      string      `json:"name"`
+	ValueUSD  jsonDecimal `json:"valueUSD"`
+	Reason    string      `json:"reason"`
+	Actor     string      `json:"actor"`
+	ExpiresAt *time.Time  `json:"expiresAt,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+type getOverridesResponse struct {
+	Overrides []override `json:"overrides"`
+}
+
+func overrideToDto(format decimalFormat, o domain.RateOverride) override {
+	resp := override{
+		Name:      o.Currency,
+		ValueUSD:  format.decimal(o.ValueUSD),
+		Reason:    o.Reason,
+		Actor:     o.Actor,
+		CreatedAt: o.CreatedAt,
+	}
+
+	if !o.ExpiresAt.IsZero() {
+		resp.ExpiresAt = &o.ExpiresAt
+	}
+
+	return resp
+}
+
+type overrideAuditEntry struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Action    string       `json:"action"`
+	ValueUSD  *jsonDecimal `json:"valueUSD,omitempty"`
+	Reason    string       `json:"reason"`
+	Actor     string       `json:"actor"`
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type getOverrideAuditResponse struct {
+	Entries []overrideAuditEntry `json:"entries"`
+}
+
+func overrideAuditEntryToDto(format decimalFormat, e domain.OverrideAuditEntry) overrideAuditEntry {
+	resp := overrideAuditEntry{
+		ID:        e.ID,
+		Name:      e.Currency,
+		Action:    string(e.Action),
+		ValueUSD:  format.nullDecimal(e.ValueUSD),
+		Reason:    e.Reason,
+		Actor:     e.Actor,
+		CreatedAt: e.CreatedAt,
+	}
+
+	if !e.ExpiresAt.IsZero() {
+		resp.ExpiresAt = &e.ExpiresAt
+	}
+
+	return resp
+}
+
+type providerHealth struct {
+	Provider            string     `json:"provider"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastLatencyMs       int64      `json:"lastLatencyMs"`
+	AvgLatencyMs        int64      `json:"avgLatencyMs"`
+	LastError           string     `json:"lastError,omitempty"`
+	LastSuccessAt       *time.Time `json:"lastSuccessAt,omitempty"`
+	LastFailureAt       *time.Time `json:"lastFailureAt,omitempty"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+type getProviderHealthResponse struct {
+	Providers []providerHealth `json:"providers"`
+}
+
+func providerHealthToDto(h domain.ProviderHealth) providerHealth {
+	return providerHealth{
+		Provider:            h.Provider,
+		State:               string(h.State),
+		ConsecutiveFailures: h.ConsecutiveFailures,
+		LastLatencyMs:       h.LastLatency.Milliseconds(),
+		AvgLatencyMs:        h.AvgLatency.Milliseconds(),
+		LastError:           h.LastError,
+		LastSuccessAt:       timeOrNil(h.LastSuccessAt),
+		LastFailureAt:       timeOrNil(h.LastFailureAt),
+		OpenedAt:            timeOrNil(h.OpenedAt),
+		UpdatedAt:           h.UpdatedAt,
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+type quotaPeriod struct {
+	Calls  int64 `json:"calls"`
+	Budget int64 `json:"budget,omitempty"`
+}
+
+type providerQuota struct {
+	Provider string      `json:"provider"`
+	Level    string      `json:"level"`
+	Daily    quotaPeriod `json:"daily"`
+	Monthly  quotaPeriod `json:"monthly"`
+}
+
+type getProviderQuotaResponse struct {
+	Providers []providerQuota `json:"providers"`
+}
+
+func providerQuotaToDto(u domain.QuotaUsage, lowWatermarkPercent int) providerQuota {
+	return providerQuota{
+		Provider: u.Provider,
+		Level:    string(u.Level(lowWatermarkPercent)),
+		Daily:    quotaPeriod{Calls: u.Daily, Budget: u.Budget.Daily},
+		Monthly:  quotaPeriod{Calls: u.Monthly, Budget: u.Budget.Monthly},
+	}
+}
+
+type setProviderSymbolRequest struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+}
+
+func (r setProviderSymbolRequest) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Provider, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.Name, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.Symbol, validation.Required, validation.Length(1, 255)),
+	); err != nil {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+type providerSymbol struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+}
+
+type getProviderSymbolsResponse struct {
+	Symbols []providerSymbol `json:"symbols"`
+}
+
+func providerSymbolToDto(s domain.ProviderSymbol) providerSymbol {
+	return providerSymbol{
+		Provider: s.Provider,
+		Name:     s.Currency,
+		Symbol:   s.Symbol,
+	}
+}
+
+type setPegRequest struct {
+	Name        string          `json:"name"`
+	Target      string          `json:"target"`
+	Tolerance   decimal.Decimal `json:"tolerance"`
+	AutoSuspend bool            `json:"autoSuspend"`
+}
+
+func (r setPegRequest) Validate() error {
+	if err := validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.Length(2, 255)),
+		validation.Field(&r.Target, validation.Required, validation.Length(2, 255)),
+	); err != nil {
+		return errInvalidInput
+	}
+
+	if r.Tolerance.IsNegative() || r.Tolerance.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return errInvalidInput
+	}
+
+	return nil
+}
+
+type peg struct {
+	Name        string       `json:"name"`
+	Target      string       `json:"target"`
+	Tolerance   jsonDecimal  `json:"tolerance"`
+	AutoSuspend bool         `json:"autoSuspend"`
+	Depegged    bool         `json:"depegged"`
+	Suspended   bool         `json:"suspended"`
+	Price       *jsonDecimal `json:"price,omitempty"`
+	Deviation   *jsonDecimal `json:"deviation,omitempty"`
+}
+
+type getPegsResponse struct {
+	Pegs []peg `json:"pegs"`
+}
+
+func pegToDto(format decimalFormat, p domain.Peg) peg {
+	resp := peg{
+		Name:        p.Currency,
+		Target:      p.Target,
+		Tolerance:   format.decimal(p.Tolerance),
+		AutoSuspend: p.AutoSuspend,
+		Depegged:    p.Depegged,
+		Suspended:   p.Suspended,
+	}
+
+	if p.Priced() {
+		price, deviation := format.decimal(p.Price()), format.decimal(p.Deviation())
+		resp.Price = &price
+		resp.Deviation = &deviation
+	}
+
+	return resp
+}
+
+type depegEvent struct {
+	ID           int64       `json:"id"`
+	Name         string      `json:"name"`
+	Target       string      `json:"target"`
+	Tolerance    jsonDecimal `json:"tolerance"`
+	StartPrice   jsonDecimal `json:"startPrice"`
+	MaxDeviation jsonDecimal `json:"maxDeviation"`
+	Suspended    bool        `json:"suspended"`
+	StartedAt    time.Time   `json:"startedAt"`
+	EndedAt      *time.Time  `json:"endedAt,omitempty"`
+}
+
+type getDepegEventsResponse struct {
+	Events []depegEvent `json:"events"`
+}
+
+func depegEventToDto(format decimalFormat, e domain.DepegEvent) depegEvent {
+	resp := depegEvent{
+		ID:           e.ID,
+		Name:         e.Currency,
+		Target:       e.Target,
+		Tolerance:    format.decimal(e.Tolerance),
+		StartPrice:   format.decimal(e.StartPrice),
+		MaxDeviation: format.decimal(e.MaxDeviation),
+		Suspended:    e.Suspended,
+		StartedAt:    e.StartedAt,
+	}
+
+	if !e.EndedAt.IsZero() {
+		resp.EndedAt = &e.EndedAt
+	}
+
+	return resp
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	actorQueryParam  = "actor"
+	reasonQueryParam = "reason"
+)
+
+// GetOverrides godoc
+//
+//	@Summary		get rate overrides
+//	@Description	get active manual rate overrides
+//	@Tags			overrides
+//	@Produce		json
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200	{object}	getOverridesResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/overrides [get]
+func (h Handler) GetOverrides(c fiber.Ctx) error {
+	overrides, err := h.Currency.GetOverrides(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get overrides")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]override, 0, len(overrides))
+
+	for i := range overrides {
+		resp = append(resp, overrideToDto(format, overrides[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getOverridesResponse{Overrides: resp})
+}
+
+// SetOverride godoc
+//
+//	@Summary		set rate override
+//	@Description	pin the value of a currency until the optional expiry, replacing the provider value; a currency suspended for leaving its peg stays unavailable
+//	@Tags			overrides
+//	@Accept			json
+//	@Produce		json
+//	@Param			override	body		setOverrideRequest	true	"override"
+//	@Success		200			{object}	defaultResponse
+//	@Failure		400			{object}	errResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/overrides [post]
+func (h Handler) SetOverride(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[setOverrideRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	override := domain.RateOverride{
+		Currency: strings.ToUpper(req.Name),
+		ValueUSD: req.ValueUSD,
+		Reason:   req.Reason,
+		Actor:    req.Actor,
+	}
+
+	if req.ExpiresAt != nil {
+		override.ExpiresAt = *req.ExpiresAt
+	}
+
+	if err := h.Currency.SetOverride(c.Context(), override); err != nil {
+		h.Logger.Error().Err(err).Msgf("set override")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+// ClearOverride godoc
+//
+//	@Summary		clear rate override
+//	@Description	clear the override of a currency so the provider value is used again
+//	@Tags			overrides
+//	@Produce		json
+//	@Param			name	path		string	true	"currency name"
+//	@Param			actor	query		string	true	"who clears the override"
+//	@Param			reason	query		string	true	"why the override is cleared"
+//	@Success		200		{object}	defaultResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/overrides/{name} [delete]
+func (h Handler) ClearOverride(c fiber.Ctx) error {
+	name := strings.ToUpper(c.Params(nameParam))
+	actor := c.Query(actorQueryParam)
+	reason := c.Query(reasonQueryParam)
+
+	if actor == "" || reason == "" {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.ClearOverride(c.Context(), name, actor, reason); err != nil {
+		h.Logger.Error().Err(err).Msgf("clear override")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+// GetOverrideAudit godoc
+//
+//	@Summary		get rate override audit
+//	@Description	get who set or cleared the overrides of a currency, newest first
+//	@Tags			overrides
+//	@Produce		json
+//	@Param			name	path		string	true	"currency name"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200		{object}	getOverrideAuditResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/overrides/{name}/audit [get]
+func (h Handler) GetOverrideAudit(c fiber.Ctx) error {
+	entries, err := h.Currency.GetOverrideAudit(c.Context(), strings.ToUpper(c.Params(nameParam)))
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get override audit")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]overrideAuditEntry, 0, len(entries))
+
+	for i := range entries {
+		resp = append(resp, overrideAuditEntryToDto(format, entries[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getOverrideAuditResponse{Entries: resp})
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+const idParam = "id"
+
+// GetPairPolicies godoc
+//
+//	@Summary		get pair policies
+//	@Description	get rules allowing or denying conversions
+//	@Tags			pairs
+//	@Produce		json
+//	@Success		200	{object}	getPairPoliciesResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/pairs [get]
+func (h Handler) GetPairPolicies(c fiber.Ctx) error {
+	policies, err := h.Currency.GetPairPolicies(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get pair policies")
+
+		return serviceErrResponse(c, err)
+	}
+
+	resp := make([]pairPolicy, 0, len(policies))
+
+	for i := range policies {
+		resp = append(resp, pairPolicyToDto(policies[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getPairPoliciesResponse{Policies: resp})
+}
+
+// SavePairPolicy godoc
+//
+//	@Summary		save pair policy
+//	@Description	allow or deny conversions between two currencies or two currency types
+//	@Tags			pairs
+//	@Accept			json
+//	@Produce		json
+//	@Param			policy	body		pairPolicyRequest	true	"policy"
+//	@Success		200		{object}	createCurrencyResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/pairs [post]
+func (h Handler) SavePairPolicy(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[pairPolicyRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	id, err := h.Currency.SavePairPolicy(c.Context(), domain.PairPolicy{
+		From:      strings.ToUpper(req.From),
+		To:        strings.ToUpper(req.To),
+		FromType:  req.FromType,
+		ToType:    req.ToType,
+		IsAllowed: req.IsAllowed,
+	})
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("save pair policy")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(createCurrencyResponse{ID: id})
+}
+
+// DeletePairPolicy godoc
+//
+//	@Summary		delete pair policy
+//	@Description	delete pair policy by id
+//	@Tags			pairs
+//	@Produce		json
+//	@Param			id	path		int	true	"policy id"
+//	@Success		200	{object}	defaultResponse
+//	@Failure		400	{object}	errResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/pairs/{id} [delete]
+func (h Handler) DeletePairPolicy(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params(idParam), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.DeletePairPolicy(c.Context(), id); err != nil {
+		h.Logger.Error().Err(err).Msgf("delete pair policy")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
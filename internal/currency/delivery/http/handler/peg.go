@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetPegs godoc
+//
+//	@Summary		get pegs
+//	@Description	get the pegged currencies with their current price in the target and depeg state
+//	@Tags			pegs
+//	@Produce		json
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200	{object}	getPegsResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/pegs [get]
+func (h Handler) GetPegs(c fiber.Ctx) error {
+	pegs, err := h.Currency.GetPegs(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get pegs")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]peg, 0, len(pegs))
+
+	for i := range pegs {
+		resp = append(resp, pegToDto(format, pegs[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getPegsResponse{Pegs: resp})
+}
+
+// SetPeg godoc
+//
+//	@Summary		set peg
+//	@Description	peg a currency to one unit of the target within the tolerance, optionally suspending conversions while depegged
+//	@Tags			pegs
+//	@Accept			json
+//	@Produce		json
+//	@Param			peg	body		setPegRequest	true	"peg"
+//	@Success		200	{object}	defaultResponse
+//	@Failure		400	{object}	errResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/pegs [post]
+func (h Handler) SetPeg(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[setPegRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.SetPeg(c.Context(), domain.Peg{
+		Currency:    strings.ToUpper(req.Name),
+		Target:      strings.ToUpper(req.Target),
+		Tolerance:   req.Tolerance,
+		AutoSuspend: req.AutoSuspend,
+	}); err != nil {
+		h.Logger.Error().Err(err).Msgf("set peg")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+// ClearPeg godoc
+//
+//	@Summary		clear peg
+//	@Description	stop monitoring the peg of a currency, closing its depeg event and lifting its suspension
+//	@Tags			pegs
+//	@Produce		json
+//	@Param			name	path		string	true	"currency name"
+//	@Success		200		{object}	defaultResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/pegs/{name} [delete]
+func (h Handler) ClearPeg(c fiber.Ctx) error {
+	if err := h.Currency.ClearPeg(c.Context(), strings.ToUpper(c.Params(nameParam))); err != nil {
+		h.Logger.Error().Err(err).Msgf("clear peg")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+// GetDepegEvents godoc
+//
+//	@Summary		get depeg events
+//	@Description	get the stretches of time a currency traded outside its peg band, newest first
+//	@Tags			pegs
+//	@Produce		json
+//	@Param			name	path		string	true	"currency name"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200		{object}	getDepegEventsResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/pegs/{name}/events [get]
+func (h Handler) GetDepegEvents(c fiber.Ctx) error {
+	events, err := h.Currency.GetDepegEvents(c.Context(), strings.ToUpper(c.Params(nameParam)))
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get depeg events")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]depegEvent, 0, len(events))
+
+	for i := range events {
+		resp = append(resp, depegEventToDto(format, events[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getDepegEventsResponse{Events: resp})
+}
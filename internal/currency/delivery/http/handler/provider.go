@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	providerParam      = "provider"
+	providerQueryParam = "provider"
+)
+
+// GetProviderHealth godoc
+//
+//	@Summary		get provider health
+//	@Description	get circuit breaker state and latency of the rates providers as last reported by the worker
+//	@Tags			providers
+//	@Produce		json
+//	@Success		200	{object}	getProviderHealthResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/providers/health [get]
+func (h Handler) GetProviderHealth(c fiber.Ctx) error {
+	health, err := h.Currency.GetProviderHealth(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get provider health")
+
+		return serviceErrResponse(c, err)
+	}
+
+	resp := make([]providerHealth, 0, len(health))
+
+	for i := range health {
+		resp = append(resp, providerHealthToDto(health[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getProviderHealthResponse{Providers: resp})
+}
+
+// GetProviderQuota godoc
+//
+//	@Summary		get provider quota
+//	@Description	get calls made to the rates providers today and this month versus their budget
+//	@Tags			providers
+//	@Produce		json
+//	@Success		200	{object}	getProviderQuotaResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/providers/quota [get]
+func (h Handler) GetProviderQuota(c fiber.Ctx) error {
+	usage, err := h.Currency.GetQuota(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get provider quota")
+
+		return serviceErrResponse(c, err)
+	}
+
+	resp := make([]providerQuota, 0, len(usage))
+
+	for i := range usage {
+		resp = append(resp, providerQuotaToDto(usage[i], h.Currency.Providers.Quota.LowWatermarkPercent))
+	}
+
+	return c.Status(http.StatusOK).JSON(getProviderQuotaResponse{Providers: resp})
+}
+
+// GetProviderSymbols godoc
+//
+//	@Summary		get provider symbols
+//	@Description	get the symbols the providers know the currencies by, when they differ from the currency names
+//	@Tags			providers
+//	@Produce		json
+//	@Param			provider	query		string	false	"provider name, all providers when empty"
+//	@Success		200			{object}	getProviderSymbolsResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/providers/symbols [get]
+func (h Handler) GetProviderSymbols(c fiber.Ctx) error {
+	symbols, err := h.Currency.GetProviderSymbols(c.Context(), c.Query(providerQueryParam))
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get provider symbols")
+
+		return serviceErrResponse(c, err)
+	}
+
+	resp := make([]providerSymbol, 0, len(symbols))
+
+	for i := range symbols {
+		resp = append(resp, providerSymbolToDto(symbols[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getProviderSymbolsResponse{Symbols: resp})
+}
+
+// SetProviderSymbol godoc
+//
+//	@Summary		set provider symbol
+//	@Description	ask the provider for the currency by the symbol, as ETH for WETH or a lowercase id
+//	@Tags			providers
+//	@Accept			json
+//	@Produce		json
+//	@Param			symbol	body		setProviderSymbolRequest	true	"symbol"
+//	@Success		200		{object}	defaultResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/providers/symbols [post]
+func (h Handler) SetProviderSymbol(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[setProviderSymbolRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.SetProviderSymbol(c.Context(), domain.ProviderSymbol{
+		Provider: req.Provider,
+		Currency: strings.ToUpper(req.Name),
+		Symbol:   req.Symbol,
+	}); err != nil {
+		h.Logger.Error().Err(err).Msgf("set provider symbol")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
+
+// DeleteProviderSymbol godoc
+//
+//	@Summary		delete provider symbol
+//	@Description	ask the provider for the currency by its name again
+//	@Tags			providers
+//	@Produce		json
+//	@Param			provider	path		string	true	"provider name"
+//	@Param			name		path		string	true	"currency name"
+//	@Success		200			{object}	defaultResponse
+//	@Failure		400			{object}	errResponse
+//	@Failure		500			{object}	errResponse
+//	@Router			/currency/providers/symbols/{provider}/{name} [delete]
+func (h Handler) DeleteProviderSymbol(c fiber.Ctx) error {
+	provider := c.Params(providerParam)
+	name := strings.ToUpper(c.Params(nameParam))
+
+	if err := h.Currency.DeleteProviderSymbol(c.Context(), provider, name); err != nil {
+		h.Logger.Error().Err(err).Msgf("delete provider symbol")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
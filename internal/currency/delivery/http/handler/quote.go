@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// CreateQuote godoc
+//
+//	@Summary		create quote
+//	@Description	convert at the current rates and lock the result until the quote expires
+//	@Tags			quote
+//	@Accept			json
+//	@Produce		json
+//	@Param			quote	body		createQuoteRequest	true	"conversion to lock"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200		{object}	quoteResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/quote [post]
+func (h Handler) CreateQuote(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[createQuoteRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	quote, err := h.Quote.Create(c.Context(), domain.Rate{
+		From:     strings.ToUpper(req.From),
+		To:       strings.ToUpper(req.To),
+		Value:    req.Value,
+		Rounding: domain.RoundingMode(req.Rounding),
+	})
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("create quote")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(quoteToDto(decimalFormatOf(c), quote))
+}
+
+// AcceptQuote godoc
+//
+//	@Summary		accept quote
+//	@Description	get the locked conversion of a quote that has not expired
+//	@Tags			quote
+//	@Produce		json
+//	@Param			id	path		string	true	"quote id"
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200	{object}	quoteResponse
+//	@Failure		400	{object}	errResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/quote/{id}/accept [post]
+func (h Handler) AcceptQuote(c fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params(idParam))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	quote, err := h.Quote.Accept(c.Context(), id.String())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("accept quote")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(quoteToDto(decimalFormatOf(c), quote))
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetSpreads godoc
+//
+//	@Summary		get spreads
+//	@Description	get markups applied around the mid rate
+//	@Tags			spreads
+//	@Produce		json
+//	@Param			decimals	query		string	false	"number to send decimals as JSON numbers instead of strings"
+//	@Success		200	{object}	getSpreadsResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/spreads [get]
+func (h Handler) GetSpreads(c fiber.Ctx) error {
+	spreads, err := h.Currency.GetSpreads(c.Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("get spreads")
+
+		return serviceErrResponse(c, err)
+	}
+
+	format := decimalFormatOf(c)
+
+	resp := make([]spread, 0, len(spreads))
+
+	for i := range spreads {
+		resp = append(resp, spreadToDto(format, spreads[i]))
+	}
+
+	return c.Status(http.StatusOK).JSON(getSpreadsResponse{Spreads: resp})
+}
+
+// SaveSpread godoc
+//
+//	@Summary		save spread
+//	@Description	set the markup of a pair, a currency or a pair of currency types
+//	@Tags			spreads
+//	@Accept			json
+//	@Produce		json
+//	@Param			spread	body		spreadRequest	true	"spread"
+//	@Success		200		{object}	createCurrencyResponse
+//	@Failure		400		{object}	errResponse
+//	@Failure		500		{object}	errResponse
+//	@Router			/currency/spreads [post]
+func (h Handler) SaveSpread(c fiber.Ctx) error {
+	req, err := ParseAndValidateRequest[spreadRequest](c.Request().Body())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	id, err := h.Currency.SaveSpread(c.Context(), domain.Spread{
+		From:     strings.ToUpper(req.From),
+		To:       strings.ToUpper(req.To),
+		Currency: strings.ToUpper(req.Currency),
+		FromType: req.FromType,
+		ToType:   req.ToType,
+		Markup:   req.Markup,
+	})
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("save spread")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(createCurrencyResponse{ID: id})
+}
+
+// DeleteSpread godoc
+//
+//	@Summary		delete spread
+//	@Description	delete spread by id
+//	@Tags			spreads
+//	@Produce		json
+//	@Param			id	path		int	true	"spread id"
+//	@Success		200	{object}	defaultResponse
+//	@Failure		400	{object}	errResponse
+//	@Failure		500	{object}	errResponse
+//	@Router			/currency/spreads/{id} [delete]
+func (h Handler) DeleteSpread(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params(idParam), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse{Error: errInvalidInput.Error()})
+	}
+
+	if err := h.Currency.DeleteSpread(c.Context(), id); err != nil {
+		h.Logger.Error().Err(err).Msgf("delete spread")
+
+		return serviceErrResponse(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(defaultResponse{Success: true})
+}
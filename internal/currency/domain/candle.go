@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateObservation is a single recorded USD value of a currency.
+type RateObservation struct {
+	ValueUSD   decimal.Decimal
+	ObservedAt time.Time
+}
+
+// Bar returns the observation as a bar whose open, high, low and close are the observed value.
+func (o RateObservation) Bar() RateBar {
+	return RateBar{
+		Start: o.ObservedAt,
+		Open:  o.ValueUSD,
+		High:  o.ValueUSD,
+		Low:   o.ValueUSD,
+		Close: o.ValueUSD,
+	}
+}
+
+// RateBar is the USD value of a currency from Start on, either a single raw observation
+// or the stored OHLC of an aggregate bucket once raw observations are purged.
+type RateBar struct {
+	Start time.Time
+	Open  decimal.Decimal
+	High  decimal.Decimal
+	Low   decimal.Decimal
+	Close decimal.Decimal
+}
+
+// flat is the bar holding the close value, the state of a currency after its bar has been applied.
+func (b RateBar) flat(start time.Time) RateBar {
+	return RateBar{Start: start, Open: b.Close, High: b.Close, Low: b.Close, Close: b.Close}
+}
+
+type CandlesRequest struct {
+	Name     string
+	Quote    string
+	Interval time.Duration
+	From     time.Time
+	To       time.Time
+}
+
+type Candle struct {
+	Start time.Time
+	Open  decimal.Decimal
+	High  decimal.Decimal
+	Low   decimal.Decimal
+	Close decimal.Decimal
+}
+
+// BuildCandles aggregates the price of one unit of base expressed in quote into OHLC buckets.
+// Bars must be sorted by start. Bars starting before from only provide their close as the
+// opening state of the window, bars starting at or after to are ignored.
+//
+// Every instant a bar of either currency starts yields a cross bar priced from the OHLC of
+// both, the currency without a bar at that instant holding its last close. The cross open
+// and close pair the opens and closes, the high and low are the widest the legs allow,
+// quote high over base low and quote low over base high, exact whenever one leg is flat.
+// A bucket wider than the interval lands in the candle of its start as the time of its
+// extremes within the bucket is not stored.
+func BuildCandles(base, quote []RateBar, from, to time.Time, interval time.Duration) []Candle {
+	type event struct {
+		bar     RateBar
+		isQuote bool
+	}
+
+	events := make([]event, 0, len(base)+len(quote))
+	for _, b := range base {
+		events = append(events, event{bar: b})
+	}
+	for _, b := range quote {
+		events = append(events, event{bar: b, isQuote: true})
+	}
+
+	for i := range events {
+		if events[i].bar.Start.Before(from) {
+			events[i].bar = events[i].bar.flat(from)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].bar.Start.Before(events[j].bar.Start) })
+
+	var (
+		candles           []Candle
+		baseBar, quoteBar RateBar
+		hasBase, hasQuote bool
+	)
+
+	for i := 0; i < len(events); {
+		at := events[i].bar.Start
+		if !at.Before(to) {
+			break
+		}
+
+		// Apply every bar starting at the same instant before emitting a price.
+		for ; i < len(events) && events[i].bar.Start.Equal(at); i++ {
+			if events[i].isQuote {
+				quoteBar, hasQuote = events[i].bar, true
+			} else {
+				baseBar, hasBase = events[i].bar, true
+			}
+		}
+
+		if !hasBase || !hasQuote || !baseBar.Low.IsPositive() || !baseBar.Close.IsPositive() || !baseBar.Open.IsPositive() {
+			continue
+		}
+
+		open := quoteBar.Open.Div(baseBar.Open)
+		high := quoteBar.High.Div(baseBar.Low)
+		low := quoteBar.Low.Div(baseBar.High)
+		closing := quoteBar.Close.Div(baseBar.Close)
+
+		// Until their next bar both currencies hold their close.
+		baseBar, quoteBar = baseBar.flat(at), quoteBar.flat(at)
+
+		start := at.Truncate(interval)
+
+		if len(candles) == 0 || !candles[len(candles)-1].Start.Equal(start) {
+			candles = append(candles, Candle{Start: start, Open: open, High: high, Low: low, Close: closing})
+			continue
+		}
+
+		candle := &candles[len(candles)-1]
+		candle.High = decimal.Max(candle.High, high)
+		candle.Low = decimal.Min(candle.Low, low)
+		candle.Close = closing
+	}
+
+	return candles
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func bar(start time.Time, open, high, low, closing string) RateBar {
+	return RateBar{
+		Start: start,
+		Open:  decimal.RequireFromString(open),
+		High:  decimal.RequireFromString(high),
+		Low:   decimal.RequireFromString(low),
+		Close: decimal.RequireFromString(closing),
+	}
+}
+
+func point(start time.Time, value string) RateBar {
+	return bar(start, value, value, value, value)
+}
+
+func TestBuildCandles(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	tests := []struct {
+		name  string
+		base  []RateBar
+		quote []RateBar
+		want  []Candle
+	}{
+		{
+			name: "aggregate against flat quote keeps the stored extremes",
+			// One USD buys 0.8 to 1 units of base, so one base is worth 1 to 1.25 USD.
+			base:  []RateBar{bar(from, "0.9", "1", "0.8", "0.95")},
+			quote: []RateBar{point(from.Add(-time.Hour), "1")},
+			want: []Candle{
+				{Start: from, Open: dec("1.1111111111111111"), High: dec("1.25"), Low: dec("1"), Close: dec("1.0526315789473684")},
+			},
+		},
+		{
+			name: "bars of both currencies at the same instant are paired",
+			base: []RateBar{
+				bar(from, "2", "2", "2", "2"),
+				bar(from.Add(time.Hour), "2", "4", "1", "2"),
+			},
+			quote: []RateBar{
+				bar(from, "4", "4", "4", "4"),
+				bar(from.Add(time.Hour), "4", "8", "2", "6"),
+			},
+			want: []Candle{
+				{Start: from, Open: dec("2"), High: dec("2"), Low: dec("2"), Close: dec("2")},
+				{Start: from.Add(time.Hour), Open: dec("2"), High: dec("8"), Low: dec("0.5"), Close: dec("3")},
+			},
+		},
+		{
+			name: "currency without a bar holds its close",
+			base: []RateBar{
+				bar(from, "1", "1", "1", "2"),
+				point(from.Add(90*time.Minute), "4"),
+			},
+			quote: []RateBar{point(from, "8")},
+			want: []Candle{
+				{Start: from, Open: dec("8"), High: dec("8"), Low: dec("8"), Close: dec("4")},
+				{Start: from.Add(time.Hour), Open: dec("2"), High: dec("2"), Low: dec("2"), Close: dec("2")},
+			},
+		},
+		{
+			name:  "bars from to on are ignored",
+			base:  []RateBar{point(from, "1"), point(to, "2")},
+			quote: []RateBar{point(from, "1")},
+			want: []Candle{
+				{Start: from, Open: dec("1"), High: dec("1"), Low: dec("1"), Close: dec("1")},
+			},
+		},
+		{
+			name:  "no candle without both currencies",
+			base:  []RateBar{point(from, "1")},
+			quote: nil,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildCandles(tt.base, tt.quote, from, to, time.Hour)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d candles, want %d: %v", len(got), len(tt.want), got)
+			}
+
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) ||
+					!got[i].Open.Equal(tt.want[i].Open) || !got[i].High.Equal(tt.want[i].High) ||
+					!got[i].Low.Equal(tt.want[i].Low) || !got[i].Close.Equal(tt.want[i].Close) {
+					t.Errorf("candle %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProviderQuote is a value of a currency supplied by a provider and the time it was observed.
+type ProviderQuote struct {
+	Provider  string
+	Value     decimal.Decimal
+	FetchedAt time.Time
+}
+
+// Consensus is the agreed value of a currency. Confidence is the share of the queried
+// providers whose quotes were accepted, from 0 to 1.
+type Consensus struct {
+	Value      decimal.Decimal
+	Accepted   []ProviderQuote
+	Rejected   []ProviderQuote
+	Confidence decimal.Decimal
+}
+
+// confidencePlaces is the number of digits the confidence is rounded to.
+const confidencePlaces = 4
+
+// BuildConsensus rejects the quotes deviating from their median by more than tolerance,
+// a fraction of the median, and returns the median of the remaining ones.
+// It reports false when there are no quotes or none of them is close enough to the median,
+// as two providers disagreeing by more than twice the tolerance: there is no telling which
+// one is right, so no value is agreed on.
+func BuildConsensus(quotes []ProviderQuote, tolerance decimal.Decimal, queried int) (Consensus, bool) {
+	if len(quotes) == 0 || queried == 0 {
+		return Consensus{}, false
+	}
+
+	sorted := make([]ProviderQuote, len(quotes))
+	copy(sorted, quotes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value.LessThan(sorted[j].Value)
+	})
+
+	median := medianOf(sorted)
+	maxDeviation := median.Abs().Mul(tolerance)
+
+	var consensus Consensus
+
+	for _, quote := range sorted {
+		if quote.Value.Sub(median).Abs().GreaterThan(maxDeviation) {
+			consensus.Rejected = append(consensus.Rejected, quote)
+			continue
+		}
+
+		consensus.Accepted = append(consensus.Accepted, quote)
+	}
+
+	if len(consensus.Accepted) == 0 {
+		return consensus, false
+	}
+
+	consensus.Value = medianOf(consensus.Accepted)
+	consensus.Confidence = decimal.NewFromInt(int64(len(consensus.Accepted))).
+		DivRound(decimal.NewFromInt(int64(queried)), confidencePlaces)
+
+	return consensus, true
+}
+
+// medianOf returns the median of quotes sorted by value.
+func medianOf(sorted []ProviderQuote) decimal.Decimal {
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle].Value
+	}
+
+	return sorted[middle-1].Value.Add(sorted[middle].Value).Div(decimal.NewFromInt(2))
+}
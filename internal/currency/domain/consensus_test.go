@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"testing"
+)
+
+func quote(provider, value string) ProviderQuote {
+	return ProviderQuote{Provider: provider, Value: dec(value)}
+}
+
+func providersOf(quotes []ProviderQuote) []string {
+	providers := make([]string, 0, len(quotes))
+	for _, quote := range quotes {
+		providers = append(providers, quote.Provider)
+	}
+
+	return providers
+}
+
+func sameProviders(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestBuildConsensus(t *testing.T) {
+	tolerance := dec("0.01")
+
+	tests := []struct {
+		name           string
+		quotes         []ProviderQuote
+		queried        int
+		wantOK         bool
+		wantValue      string
+		wantAccepted   []string
+		wantRejected   []string
+		wantConfidence string
+	}{
+		{
+			name:    "no quotes",
+			queried: 2,
+		},
+		{
+			name:           "single quote",
+			quotes:         []ProviderQuote{quote("a", "0.9")},
+			queried:        3,
+			wantOK:         true,
+			wantValue:      "0.9",
+			wantAccepted:   []string{"a"},
+			wantConfidence: "0.3333",
+		},
+		{
+			name:           "odd count takes the middle quote",
+			quotes:         []ProviderQuote{quote("a", "1.004"), quote("b", "1"), quote("c", "0.998")},
+			queried:        3,
+			wantOK:         true,
+			wantValue:      "1",
+			wantAccepted:   []string{"c", "b", "a"},
+			wantConfidence: "1",
+		},
+		{
+			name:           "outlier is rejected",
+			quotes:         []ProviderQuote{quote("a", "1"), quote("b", "1.5"), quote("c", "1.002")},
+			queried:        3,
+			wantOK:         true,
+			wantValue:      "1.001",
+			wantAccepted:   []string{"a", "c"},
+			wantRejected:   []string{"b"},
+			wantConfidence: "0.6667",
+		},
+		{
+			name:           "two close quotes take the midpoint",
+			quotes:         []ProviderQuote{quote("a", "1.004"), quote("b", "1")},
+			queried:        2,
+			wantOK:         true,
+			wantValue:      "1.002",
+			wantAccepted:   []string{"b", "a"},
+			wantConfidence: "1",
+		},
+		{
+			name:    "two disagreeing quotes give no consensus",
+			quotes:  []ProviderQuote{quote("a", "1.1"), quote("b", "1")},
+			queried: 2,
+		},
+		{
+			name:    "even split gives no consensus",
+			quotes:  []ProviderQuote{quote("a", "1"), quote("b", "1.005"), quote("c", "1.1"), quote("d", "1.3")},
+			queried: 4,
+		},
+		{
+			name:           "even count rejects an outlier around the midpoint",
+			quotes:         []ProviderQuote{quote("a", "1"), quote("b", "1.5"), quote("c", "1.002"), quote("d", "1.004")},
+			queried:        4,
+			wantOK:         true,
+			wantValue:      "1.002",
+			wantAccepted:   []string{"a", "c", "d"},
+			wantRejected:   []string{"b"},
+			wantConfidence: "0.75",
+		},
+		{
+			name:           "provider that failed lowers the confidence",
+			quotes:         []ProviderQuote{quote("a", "1"), quote("b", "1")},
+			queried:        4,
+			wantOK:         true,
+			wantValue:      "1",
+			wantAccepted:   []string{"a", "b"},
+			wantConfidence: "0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := BuildConsensus(tt.quotes, tolerance, tt.queried)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+
+			if !ok {
+				return
+			}
+
+			if !got.Value.Equal(dec(tt.wantValue)) {
+				t.Errorf("got value %s, want %s", got.Value, tt.wantValue)
+			}
+
+			if !got.Confidence.Equal(dec(tt.wantConfidence)) {
+				t.Errorf("got confidence %s, want %s", got.Confidence, tt.wantConfidence)
+			}
+
+			if accepted := providersOf(got.Accepted); !sameProviders(accepted, tt.wantAccepted) {
+				t.Errorf("got accepted %v, want %v", accepted, tt.wantAccepted)
+			}
+
+			if rejected := providersOf(got.Rejected); !sameProviders(rejected, tt.wantRejected) {
+				t.Errorf("got rejected %v, want %v", rejected, tt.wantRejected)
+			}
+		})
+	}
+}
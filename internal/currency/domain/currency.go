@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/shopspring/decimal"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type CurrencyType string
 
@@ -15,21 +19,71 @@ type Currency struct {
 	Type        CurrencyType
 	ValueUSD    decimal.Decimal
 	IsAvailable bool
+	// Precision is the number of minor unit digits, e.g. 0 for JPY, 2 for USD and 18 for ETH.
+	Precision int32
+	// Provider is the name of the provider that supplied ValueUSD, OverrideProvider for a manual override.
+	Provider string
+	// Confidence is only set when ValueUSD is a consensus of several providers.
+	Confidence decimal.NullDecimal
+	// Priority decides which currencies are still refreshed when a provider budget runs low, higher goes first.
+	Priority  int32
+	UpdatedAt time.Time
 }
 
 type CurrencyUpdateData struct {
 	Name        string
 	ValueUSD    decimal.Decimal
 	IsAvailable bool
+	Provider    string
+	FetchedAt   time.Time
+	Confidence  decimal.NullDecimal
 }
 
+// CurrencyWithValue is a fetched value. Confidence is only set for a consensus of several providers.
 type CurrencyWithValue struct {
-	Name  string
-	Value decimal.Decimal
+	Name       string
+	Value      decimal.Decimal
+	Provider   string
+	FetchedAt  time.Time
+	Confidence decimal.NullDecimal
 }
 
+// Rate is a conversion request. A zero At means "use the current rates",
+// otherwise the rates in effect at that instant are used.
+// An empty Rounding means the service default.
 type Rate struct {
-	From  string
-	To    string
-	Value decimal.Decimal
+	From     string
+	To       string
+	Value    decimal.Decimal
+	At       time.Time
+	Rounding RoundingMode
+}
+
+// Conversion is the converted amount rounded to the precision of the target currency.
+// The amount is converted at the bid, Mid, Bid and Ask are prices of one unit of the source
+// currency in the target currency and Markup is the spread applied on each side of Mid.
+// Amount is the gross amount, Net is what is left of it once Fee is charged.
+type Conversion struct {
+	Amount    decimal.Decimal
+	Fee       Fee
+	Net       decimal.Decimal
+	Precision int32
+	Rounding  RoundingMode
+	Mid       decimal.Decimal
+	Bid       decimal.Decimal
+	Ask       decimal.Decimal
+	Markup    decimal.Decimal
+}
+
+// ConversionResult is the outcome of a single conversion of a batch.
+type ConversionResult struct {
+	Conversion Conversion
+	Err        error
+}
+
+// CrossRate is the amount of To currency one unit of From currency is worth.
+type CrossRate struct {
+	From string
+	To   string
+	Rate decimal.Decimal
 }
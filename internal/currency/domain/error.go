@@ -1,11 +1,18 @@
 package domain
 
 const (
-	ErrNothingUpdated       = "nothing updated"
-	ErrNothingFound         = "nothing found"
-	ErrInvalidCurrencyTypes = "cannot change currencies with equal types"
-	ErrDuplicateValue       = "value already exists"
-	ErrValueCannotBeZero    = "value cannot be zero"
+	ErrNothingUpdated      = "nothing updated"
+	ErrNothingFound        = "nothing found"
+	ErrCurrencyUnavailable = "currency is unavailable"
+	ErrPairNotAllowed      = "conversion between these currencies is not allowed"
+	ErrDuplicateValue      = "value already exists"
+	ErrValueCannotBeZero   = "value cannot be zero"
+	ErrNoRateAtTime        = "no rate recorded at requested time"
+	ErrTooManyCandles      = "requested range contains too many candles"
+	ErrEqualCurrencies     = "currencies must differ"
+	ErrQuoteExpired        = "quote expired"
+	ErrAmountBelowFee      = "amount does not cover fees"
+	ErrUnknownProvider     = "unknown provider"
 )
 
 type ErrType string
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// FeeSchedule is the fee charged on conversions of a pair. Fees are expressed in the target
+// currency and computed on the gross converted amount. When tiers are set, the tier with the
+// largest FromAmount not above the gross amount replaces the base fixed and percentage fee.
+type FeeSchedule struct {
+	ID         int64
+	From       string
+	To         string
+	Fixed      decimal.Decimal
+	Percentage decimal.Decimal
+	MinFee     decimal.NullDecimal
+	MaxFee     decimal.NullDecimal
+	Tiers      []FeeTier
+}
+
+type FeeTier struct {
+	FromAmount decimal.Decimal
+	Fixed      decimal.Decimal
+	Percentage decimal.Decimal
+}
+
+// Fee is the breakdown of the fee charged on a conversion. CapAdjustment is what the min
+// or max cap adds to the sum of both parts, so Total is always the sum of the three lines.
+type Fee struct {
+	Fixed         decimal.Decimal
+	Percentage    decimal.Decimal
+	CapAdjustment decimal.Decimal
+	Total         decimal.Decimal
+}
+
+// Apply computes the fee of the gross amount, rounding its parts to the target precision.
+func (s FeeSchedule) Apply(gross decimal.Decimal, precision int32, rounding RoundingMode) Fee {
+	fixed, percentage := s.Fixed, s.Percentage
+
+	tiers := append([]FeeTier(nil), s.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].FromAmount.LessThan(tiers[j].FromAmount) })
+
+	for _, tier := range tiers {
+		if tier.FromAmount.GreaterThan(gross) {
+			break
+		}
+
+		fixed, percentage = tier.Fixed, tier.Percentage
+	}
+
+	fee := Fee{
+		Fixed:      rounding.Round(fixed, precision),
+		Percentage: rounding.Round(gross.Mul(percentage), precision),
+	}
+	fee.Total = fee.Fixed.Add(fee.Percentage)
+
+	if s.MinFee.Valid && fee.Total.LessThan(s.MinFee.Decimal) {
+		fee.Total = rounding.Round(s.MinFee.Decimal, precision)
+	}
+
+	if s.MaxFee.Valid && fee.Total.GreaterThan(s.MaxFee.Decimal) {
+		fee.Total = rounding.Round(s.MaxFee.Decimal, precision)
+	}
+
+	fee.CapAdjustment = fee.Total.Sub(fee.Fixed).Sub(fee.Percentage)
+
+	return fee
+}
+
+type FeeSchedules []FeeSchedule
+
+func (fs FeeSchedules) For(from, to string) (FeeSchedule, bool) {
+	for _, s := range fs {
+		if s.From == from && s.To == to {
+			return s, true
+		}
+	}
+
+	return FeeSchedule{}, false
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func nullDec(value string) decimal.NullDecimal {
+	return decimal.NewNullDecimal(dec(value))
+}
+
+func TestFeeScheduleApply(t *testing.T) {
+	tiered := FeeSchedule{
+		Fixed:      dec("1"),
+		Percentage: dec("0.02"),
+		Tiers: []FeeTier{
+			// Unsorted on purpose, tiers are matched by amount and not by position.
+			{FromAmount: dec("1000"), Fixed: dec("0"), Percentage: dec("0.005")},
+			{FromAmount: dec("100"), Fixed: dec("0.5"), Percentage: dec("0.01")},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		schedule  FeeSchedule
+		gross     string
+		precision int32
+		rounding  RoundingMode
+		want      Fee
+	}{
+		{
+			name:      "base fee below the first tier",
+			schedule:  tiered,
+			gross:     "50",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("1"), Percentage: dec("1"), CapAdjustment: dec("0"), Total: dec("2")},
+		},
+		{
+			name:      "tier starts at its from amount",
+			schedule:  tiered,
+			gross:     "100",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("0.5"), Percentage: dec("1"), CapAdjustment: dec("0"), Total: dec("1.5")},
+		},
+		{
+			name:      "largest tier not above the amount wins",
+			schedule:  tiered,
+			gross:     "5000",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("0"), Percentage: dec("25"), CapAdjustment: dec("0"), Total: dec("25")},
+		},
+		{
+			name:      "parts are rounded half even",
+			schedule:  FeeSchedule{Fixed: dec("0.125"), Percentage: dec("0.01")},
+			gross:     "12.5",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("0.12"), Percentage: dec("0.12"), CapAdjustment: dec("0"), Total: dec("0.24")},
+		},
+		{
+			name:      "parts are rounded half up",
+			schedule:  FeeSchedule{Fixed: dec("0.125"), Percentage: dec("0.01")},
+			gross:     "12.5",
+			precision: 2,
+			rounding:  HalfUp,
+			want:      Fee{Fixed: dec("0.13"), Percentage: dec("0.13"), CapAdjustment: dec("0"), Total: dec("0.26")},
+		},
+		{
+			name:      "parts are rounded up with ceiling",
+			schedule:  FeeSchedule{Percentage: dec("0.001")},
+			gross:     "1",
+			precision: 2,
+			rounding:  Ceiling,
+			want:      Fee{Fixed: dec("0"), Percentage: dec("0.01"), CapAdjustment: dec("0"), Total: dec("0.01")},
+		},
+		{
+			name:      "min fee adds a positive adjustment",
+			schedule:  FeeSchedule{Fixed: dec("0.1"), Percentage: dec("0.01"), MinFee: nullDec("1")},
+			gross:     "10",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("0.1"), Percentage: dec("0.1"), CapAdjustment: dec("0.8"), Total: dec("1")},
+		},
+		{
+			name:      "max fee adds a negative adjustment",
+			schedule:  FeeSchedule{Fixed: dec("1"), Percentage: dec("0.01"), MaxFee: nullDec("5")},
+			gross:     "1000",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("1"), Percentage: dec("10"), CapAdjustment: dec("-6"), Total: dec("5")},
+		},
+		{
+			name:      "fee within the caps is kept",
+			schedule:  FeeSchedule{Fixed: dec("1"), Percentage: dec("0.01"), MinFee: nullDec("1"), MaxFee: nullDec("5")},
+			gross:     "100",
+			precision: 2,
+			rounding:  HalfEven,
+			want:      Fee{Fixed: dec("1"), Percentage: dec("1"), CapAdjustment: dec("0"), Total: dec("2")},
+		},
+		{
+			name:      "caps are rounded to the precision",
+			schedule:  FeeSchedule{MinFee: nullDec("0.125")},
+			gross:     "10",
+			precision: 2,
+			rounding:  Floor,
+			want:      Fee{Fixed: dec("0"), Percentage: dec("0"), CapAdjustment: dec("0.12"), Total: dec("0.12")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.Apply(dec(tt.gross), tt.precision, tt.rounding)
+
+			if !got.Fixed.Equal(tt.want.Fixed) || !got.Percentage.Equal(tt.want.Percentage) ||
+				!got.CapAdjustment.Equal(tt.want.CapAdjustment) || !got.Total.Equal(tt.want.Total) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+
+			if sum := got.Fixed.Add(got.Percentage).Add(got.CapAdjustment); !sum.Equal(got.Total) {
+				t.Errorf("lines sum to %s, total is %s", sum, got.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Resolution string
+
+const (
+	Hour Resolution = "hour"
+	Day  Resolution = "day"
+)
+
+func (r Resolution) Duration() time.Duration {
+	if r == Day {
+		return 24 * time.Hour
+	}
+
+	return time.Hour
+}
+
+type RateAggregate struct {
+	Resolution  Resolution
+	BucketStart time.Time
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+}
+
+// Bar returns the stored OHLC of the bucket.
+func (a RateAggregate) Bar() RateBar {
+	return RateBar{
+		Start: a.BucketStart,
+		Open:  a.Open,
+		High:  a.High,
+		Low:   a.Low,
+		Close: a.Close,
+	}
+}
+
+// RetentionPolicy describes how long rate history is kept.
+// Raw observations older than DownsampleAfter are rolled up into hourly and daily
+// aggregates, raw observations older than RawRetention and hourly aggregates older
+// than HourlyRetention are deleted. Daily aggregates are kept forever.
+type RetentionPolicy struct {
+	DownsampleAfter time.Duration
+	RawRetention    time.Duration
+	HourlyRetention time.Duration
+}
+
+// Validate makes sure every raw observation gets aggregated at least once before it is purged.
+func (p RetentionPolicy) Validate() error {
+	if p.DownsampleAfter <= 0 || p.RawRetention < p.DownsampleAfter+Day.Duration() {
+		return errors.New("raw retention must exceed downsample age by at least one day")
+	}
+
+	if p.HourlyRetention < p.RawRetention {
+		return errors.New("hourly retention must not be shorter than raw retention")
+	}
+
+	return nil
+}
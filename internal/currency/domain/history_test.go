@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicyValidate(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name    string
+		policy  RetentionPolicy
+		wantErr bool
+	}{
+		{name: "valid", policy: RetentionPolicy{DownsampleAfter: day, RawRetention: 7 * day, HourlyRetention: 90 * day}},
+		{name: "raw retention one day past downsample", policy: RetentionPolicy{DownsampleAfter: day, RawRetention: 2 * day, HourlyRetention: 2 * day}},
+		{name: "no downsampling", policy: RetentionPolicy{RawRetention: 7 * day, HourlyRetention: 90 * day}, wantErr: true},
+		{name: "raw purged too early", policy: RetentionPolicy{DownsampleAfter: day, RawRetention: 36 * time.Hour, HourlyRetention: 90 * day}, wantErr: true},
+		{name: "hourly shorter than raw", policy: RetentionPolicy{DownsampleAfter: day, RawRetention: 7 * day, HourlyRetention: 6 * day}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
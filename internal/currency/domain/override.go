@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateOverride pins the value of a currency by hand, taking precedence over the provider value
+// until it expires or is cleared. A zero ExpiresAt means the override never expires.
+type RateOverride struct {
+	Currency  string
+	ValueUSD  decimal.Decimal
+	Reason    string
+	Actor     string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// OverrideProvider is reported as the provider of a currency whose value is overridden.
+const OverrideProvider = "override"
+
+type OverrideAction string
+
+const (
+	OverrideSet   OverrideAction = "set"
+	OverrideClear OverrideAction = "clear"
+)
+
+// OverrideAuditEntry records who set or cleared an override and why.
+// ValueUSD and ExpiresAt are only set for OverrideSet entries.
+type OverrideAuditEntry struct {
+	ID        int64
+	Currency  string
+	Action    OverrideAction
+	ValueUSD  decimal.NullDecimal
+	Reason    string
+	Actor     string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,41 @@
+package domain
+
+// PairPolicy allows or denies conversions either between two specific currencies
+// (From and To are set) or between two currency types (FromType and ToType are set).
+type PairPolicy struct {
+	ID        int64
+	From      string
+	To        string
+	FromType  CurrencyType
+	ToType    CurrencyType
+	IsAllowed bool
+}
+
+func (p PairPolicy) IsPairRule() bool {
+	return p.From != ""
+}
+
+type PairPolicies []PairPolicy
+
+// IsAllowed reports whether converting from one currency to another is permitted.
+// A rule for the specific pair wins over a rule for the currency types,
+// conversions without any matching rule are denied.
+func (ps PairPolicies) IsAllowed(from, to Currency) bool {
+	typeRule, hasTypeRule := PairPolicy{}, false
+
+	for _, p := range ps {
+		if p.IsPairRule() {
+			if p.From == from.Name && p.To == to.Name {
+				return p.IsAllowed
+			}
+
+			continue
+		}
+
+		if p.FromType == from.Type && p.ToType == to.Type {
+			typeRule, hasTypeRule = p, true
+		}
+	}
+
+	return hasTypeRule && typeRule.IsAllowed
+}
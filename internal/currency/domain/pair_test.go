@@ -0,0 +1,46 @@
+package domain
+
+import "testing"
+
+func TestPairPoliciesIsAllowed(t *testing.T) {
+	usd := Currency{Name: "USD", Type: Fiat}
+	eur := Currency{Name: "EUR", Type: Fiat}
+	btc := Currency{Name: "BTC", Type: Crypto}
+	eth := Currency{Name: "ETH", Type: Crypto}
+
+	policies := PairPolicies{
+		{From: "BTC", To: "ETH", IsAllowed: false},
+		{FromType: Crypto, ToType: Crypto, IsAllowed: true},
+		{FromType: Fiat, ToType: Crypto, IsAllowed: true},
+		{From: "USD", To: "BTC", IsAllowed: false},
+	}
+
+	tests := []struct {
+		name     string
+		policies PairPolicies
+		from, to Currency
+		want     bool
+	}{
+		{name: "no policies deny", from: usd, to: eur, want: false},
+		{name: "type rule allows", policies: policies, from: eth, to: btc, want: true},
+		{name: "pair rule wins over an earlier type rule", policies: policies, from: usd, to: btc, want: false},
+		{name: "pair rule wins over a later type rule", policies: policies, from: btc, to: eth, want: false},
+		{name: "pair rule is directional", policies: policies, from: eur, to: btc, want: true},
+		{name: "no matching rule denies", policies: policies, from: btc, to: usd, want: false},
+		{
+			name:     "pair rule allows against a denying type rule",
+			policies: PairPolicies{{FromType: Fiat, ToType: Fiat}, {From: "USD", To: "EUR", IsAllowed: true}},
+			from:     usd,
+			to:       eur,
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policies.IsAllowed(tt.from, tt.to); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// pegPriceDigits are kept when pricing a pegged currency in its target.
+const pegPriceDigits = 8
+
+// Peg is the target a stablecoin tracks: one unit of Currency is worth one unit of Target,
+// give or take Tolerance. ValueUSD and TargetValueUSD are the provider values, overrides aside.
+// Depegged is set while a depeg event is open and Suspended while it keeps conversions through
+// the currency unavailable, which only happens with AutoSuspend.
+type Peg struct {
+	Currency       string
+	Target         string
+	Tolerance      decimal.Decimal
+	AutoSuspend    bool
+	Depegged       bool
+	Suspended      bool
+	ValueUSD       decimal.Decimal
+	TargetValueUSD decimal.Decimal
+}
+
+// Priced tells whether both values are known, a currency never fetched has a zero value.
+func (p Peg) Priced() bool {
+	return p.ValueUSD.IsPositive() && p.TargetValueUSD.IsPositive()
+}
+
+// Price is the worth of one unit of the currency in units of the target.
+func (p Peg) Price() decimal.Decimal {
+	if !p.Priced() {
+		return decimal.Zero
+	}
+
+	return p.TargetValueUSD.DivRound(p.ValueUSD, pegPriceDigits)
+}
+
+// Deviation is how far the price is from the peg, in either direction.
+func (p Peg) Deviation() decimal.Decimal {
+	return p.Price().Sub(decimal.NewFromInt(1)).Abs()
+}
+
+func (p Peg) InBand() bool {
+	return p.Deviation().LessThanOrEqual(p.Tolerance)
+}
+
+// DepegEvent records a stretch of time the currency traded outside its peg band.
+// MaxDeviation is the largest deviation seen, a zero EndedAt means the event is still open.
+type DepegEvent struct {
+	ID           int64
+	Currency     string
+	Target       string
+	Tolerance    decimal.Decimal
+	StartPrice   decimal.Decimal
+	MaxDeviation decimal.Decimal
+	Suspended    bool
+	StartedAt    time.Time
+	EndedAt      time.Time
+}
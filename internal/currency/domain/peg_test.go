@@ -0,0 +1,66 @@
+package domain
+
+import "testing"
+
+func TestPegInBand(t *testing.T) {
+	tests := []struct {
+		name          string
+		peg           Peg
+		wantPrice     string
+		wantDeviation string
+		wantInBand    bool
+	}{
+		{
+			name:          "at par",
+			peg:           Peg{Tolerance: dec("0.01"), ValueUSD: dec("1"), TargetValueUSD: dec("1")},
+			wantPrice:     "1",
+			wantDeviation: "0",
+			wantInBand:    true,
+		},
+		{
+			name:          "on the band edge",
+			peg:           Peg{Tolerance: dec("0.01"), ValueUSD: dec("1"), TargetValueUSD: dec("1.01")},
+			wantPrice:     "1.01",
+			wantDeviation: "0.01",
+			wantInBand:    true,
+		},
+		{
+			name: "below the band",
+			// One USD buys 1.25 units, so one unit is worth 0.8 USD.
+			peg:           Peg{Tolerance: dec("0.01"), ValueUSD: dec("1.25"), TargetValueUSD: dec("1")},
+			wantPrice:     "0.8",
+			wantDeviation: "0.2",
+			wantInBand:    false,
+		},
+		{
+			name:          "against a non USD target",
+			peg:           Peg{Tolerance: dec("0.005"), ValueUSD: dec("0.9"), TargetValueUSD: dec("0.9036")},
+			wantPrice:     "1.004",
+			wantDeviation: "0.004",
+			wantInBand:    true,
+		},
+		{
+			name:          "not priced",
+			peg:           Peg{Tolerance: dec("0.01"), TargetValueUSD: dec("1")},
+			wantPrice:     "0",
+			wantDeviation: "1",
+			wantInBand:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.peg.Price(); !got.Equal(dec(tt.wantPrice)) {
+				t.Errorf("got price %s, want %s", got, tt.wantPrice)
+			}
+
+			if got := tt.peg.Deviation(); !got.Equal(dec(tt.wantDeviation)) {
+				t.Errorf("got deviation %s, want %s", got, tt.wantDeviation)
+			}
+
+			if got := tt.peg.InBand(); got != tt.wantInBand {
+				t.Errorf("got in band %v, want %v", got, tt.wantInBand)
+			}
+		})
+	}
+}
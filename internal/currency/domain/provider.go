@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// ProviderHealth is the circuit breaker state of a rates provider. AvgLatency is a moving
+// average of the request latency and LastError is the error of the last failed request.
+type ProviderHealth struct {
+	Provider            string
+	State               BreakerState
+	ConsecutiveFailures int
+	LastLatency         time.Duration
+	AvgLatency          time.Duration
+	LastError           string
+	LastSuccessAt       time.Time
+	LastFailureAt       time.Time
+	OpenedAt            time.Time
+	UpdatedAt           time.Time
+}
+
+type ProviderErrorKind string
+
+const (
+	ProviderUnauthorized     ProviderErrorKind = "unauthorized"
+	ProviderQuotaExceeded    ProviderErrorKind = "quota_exceeded"
+	ProviderRateLimited      ProviderErrorKind = "rate_limited"
+	ProviderUnknownSymbol    ProviderErrorKind = "unknown_symbol"
+	ProviderMalformedPayload ProviderErrorKind = "malformed_payload"
+	ProviderUpstream         ProviderErrorKind = "upstream"
+)
+
+// ProviderError is a failed provider call. Status is the HTTP status of the response, if any,
+// and Message the error reported by the provider or the reason the response was rejected.
+type ProviderError struct {
+	Provider string
+	Kind     ProviderErrorKind
+	Status   int
+	Message  string
+}
+
+func NewProviderError(provider string, kind ProviderErrorKind, status int, message string) error {
+	return &ProviderError{
+		Provider: provider,
+		Kind:     kind,
+		Status:   status,
+		Message:  message,
+	}
+}
+
+func (e ProviderError) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("provider %s: %s (status %d): %s", e.Provider, e.Kind, e.Status, e.Message)
+	}
+
+	return fmt.Sprintf("provider %s: %s: %s", e.Provider, e.Kind, e.Message)
+}
+
+// DisablesProvider tells whether the provider will keep failing until someone fixes
+// the credentials or the quota resets, so it should not be asked again for now.
+func (e ProviderError) DisablesProvider() bool {
+	return e.Kind == ProviderUnauthorized || e.Kind == ProviderQuotaExceeded
+}
+
+// ProviderErrorKindOf returns the kind of the provider error wrapped in err.
+func ProviderErrorKindOf(err error) (ProviderErrorKind, bool) {
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) {
+		return "", false
+	}
+
+	return providerErr.Kind, true
+}
+
+// ProviderSymbol is the ticker or id a provider knows the currency by, when it differs from the currency name.
+type ProviderSymbol struct {
+	Provider string
+	Currency string
+	Symbol   string
+}
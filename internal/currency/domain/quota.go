@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// QuotaBudget is the number of calls a provider bills per period, zero means unlimited.
+type QuotaBudget struct {
+	Daily   int64
+	Monthly int64
+}
+
+type QuotaLevel string
+
+const (
+	QuotaOK        QuotaLevel = "ok"
+	QuotaLow       QuotaLevel = "low"
+	QuotaExhausted QuotaLevel = "exhausted"
+)
+
+// QuotaPolicy tells how to save calls once the budget of a provider runs low.
+type QuotaPolicy struct {
+	LowWatermarkPercent int
+	LowRefreshInterval  time.Duration
+	MinPriority         int32
+}
+
+// QuotaUsage is the number of calls made to a provider in the current day and month.
+type QuotaUsage struct {
+	Provider string
+	Daily    int64
+	Monthly  int64
+	Budget   QuotaBudget
+}
+
+// Remaining returns the calls left in the tightest budget, reporting false when the provider is unlimited.
+func (u QuotaUsage) Remaining() (int64, bool) {
+	var (
+		remaining int64
+		limited   bool
+	)
+
+	for _, period := range []struct{ used, budget int64 }{
+		{u.Daily, u.Budget.Daily},
+		{u.Monthly, u.Budget.Monthly},
+	} {
+		if period.budget <= 0 {
+			continue
+		}
+
+		if left := period.budget - period.used; !limited || left < remaining {
+			remaining, limited = left, true
+		}
+	}
+
+	return remaining, limited
+}
+
+// Level returns the state of the tightest budget. A budget is low once less than
+// lowWatermarkPercent of it is left and exhausted once nothing is left.
+func (u QuotaUsage) Level(lowWatermarkPercent int) QuotaLevel {
+	level := QuotaOK
+
+	for _, period := range []struct{ used, budget int64 }{
+		{u.Daily, u.Budget.Daily},
+		{u.Monthly, u.Budget.Monthly},
+	} {
+		if period.budget <= 0 {
+			continue
+		}
+
+		remaining := period.budget - period.used
+		if remaining <= 0 {
+			return QuotaExhausted
+		}
+
+		if remaining*100 < period.budget*int64(lowWatermarkPercent) {
+			level = QuotaLow
+		}
+	}
+
+	return level
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "testing"
+
+func TestQuotaUsageLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage QuotaUsage
+		want  QuotaLevel
+	}{
+		{name: "unlimited", usage: QuotaUsage{Daily: 1000, Monthly: 100000}, want: QuotaOK},
+		{name: "plenty left", usage: QuotaUsage{Daily: 10, Budget: QuotaBudget{Daily: 100}}, want: QuotaOK},
+		{name: "watermark itself is not low", usage: QuotaUsage{Daily: 80, Budget: QuotaBudget{Daily: 100}}, want: QuotaOK},
+		{name: "below the watermark", usage: QuotaUsage{Daily: 81, Budget: QuotaBudget{Daily: 100}}, want: QuotaLow},
+		{name: "daily exhausted", usage: QuotaUsage{Daily: 100, Budget: QuotaBudget{Daily: 100}}, want: QuotaExhausted},
+		{name: "overrun is exhausted", usage: QuotaUsage{Daily: 120, Budget: QuotaBudget{Daily: 100}}, want: QuotaExhausted},
+		{
+			name:  "tightest budget wins",
+			usage: QuotaUsage{Daily: 1, Monthly: 950, Budget: QuotaBudget{Daily: 100, Monthly: 1000}},
+			want:  QuotaLow,
+		},
+		{
+			name:  "exhausted month wins over a low day",
+			usage: QuotaUsage{Daily: 90, Monthly: 1000, Budget: QuotaBudget{Daily: 100, Monthly: 1000}},
+			want:  QuotaExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.usage.Level(20); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaUsageRemaining(t *testing.T) {
+	tests := []struct {
+		name        string
+		usage       QuotaUsage
+		want        int64
+		wantLimited bool
+	}{
+		{name: "unlimited", usage: QuotaUsage{Daily: 1000}},
+		{name: "daily", usage: QuotaUsage{Daily: 30, Budget: QuotaBudget{Daily: 100}}, want: 70, wantLimited: true},
+		{
+			name:        "tightest budget",
+			usage:       QuotaUsage{Daily: 30, Monthly: 990, Budget: QuotaBudget{Daily: 100, Monthly: 1000}},
+			want:        10,
+			wantLimited: true,
+		},
+		{name: "overrun", usage: QuotaUsage{Monthly: 1005, Budget: QuotaBudget{Monthly: 1000}}, want: -5, wantLimited: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, limited := tt.usage.Remaining()
+			if got != tt.want || limited != tt.wantLimited {
+				t.Errorf("got %d %v, want %d %v", got, limited, tt.want, tt.wantLimited)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote is a conversion locked until ExpiresAt. A zero AcceptedAt means the quote was not accepted yet.
+type Quote struct {
+	ID         string
+	From       string
+	To         string
+	Value      decimal.Decimal
+	Conversion Conversion
+	ExpiresAt  time.Time
+	AcceptedAt time.Time
+}
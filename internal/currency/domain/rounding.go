@@ -0,0 +1,39 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+type RoundingMode string
+
+const (
+	HalfEven RoundingMode = "half_even"
+	HalfUp   RoundingMode = "half_up"
+	Floor    RoundingMode = "floor"
+	Ceiling  RoundingMode = "ceiling"
+)
+
+// DefaultPrecision is the number of minor unit digits of a currency created without an explicit precision.
+const DefaultPrecision int32 = 2
+
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case HalfEven, HalfUp, Floor, Ceiling:
+		return true
+	}
+
+	return false
+}
+
+// Round rounds the value to the given number of decimal places.
+// Half-up rounds halves away from zero, as is common in accounting.
+func (m RoundingMode) Round(value decimal.Decimal, places int32) decimal.Decimal {
+	switch m {
+	case HalfUp:
+		return value.Round(places)
+	case Floor:
+		return value.RoundFloor(places)
+	case Ceiling:
+		return value.RoundCeil(places)
+	default:
+		return value.RoundBank(places)
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// Spread is a markup applied around the mid rate. It targets either a specific pair (From and To),
+// every pair involving a currency (Currency) or a pair of currency types (FromType and ToType).
+// Markup is a fraction of the mid rate, e.g. 0.005 for half a percent on each side.
+type Spread struct {
+	ID       int64
+	From     string
+	To       string
+	Currency string
+	FromType CurrencyType
+	ToType   CurrencyType
+	Markup   decimal.Decimal
+}
+
+type Spreads []Spread
+
+// MarkupFor returns the markup of the conversion. A pair spread wins over currency spreads,
+// which win over a type spread. When both currencies have a spread, the larger one is used.
+func (ss Spreads) MarkupFor(from, to Currency) decimal.Decimal {
+	var (
+		currencyMarkup, typeMarkup       decimal.Decimal
+		hasCurrencyMarkup, hasTypeMarkup bool
+	)
+
+	for _, s := range ss {
+		switch {
+		case s.From != "":
+			if s.From == from.Name && s.To == to.Name {
+				return s.Markup
+			}
+		case s.Currency != "":
+			if s.Currency == from.Name || s.Currency == to.Name {
+				currencyMarkup, hasCurrencyMarkup = decimal.Max(currencyMarkup, s.Markup), true
+			}
+		case s.FromType == from.Type && s.ToType == to.Type:
+			typeMarkup, hasTypeMarkup = s.Markup, true
+		}
+	}
+
+	if hasCurrencyMarkup {
+		return currencyMarkup
+	}
+
+	if hasTypeMarkup {
+		return typeMarkup
+	}
+
+	return decimal.Zero
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "testing"
+
+func TestSpreadsMarkupFor(t *testing.T) {
+	usd := Currency{Name: "USD", Type: Fiat}
+	eur := Currency{Name: "EUR", Type: Fiat}
+	btc := Currency{Name: "BTC", Type: Crypto}
+	eth := Currency{Name: "ETH", Type: Crypto}
+
+	spreads := Spreads{
+		{FromType: Fiat, ToType: Crypto, Markup: dec("0.01")},
+		{Currency: "BTC", Markup: dec("0.002")},
+		{Currency: "ETH", Markup: dec("0.003")},
+		{From: "EUR", To: "BTC", Markup: dec("0.0005")},
+	}
+
+	tests := []struct {
+		name     string
+		from, to Currency
+		want     string
+	}{
+		{name: "pair spread wins", from: eur, to: btc, want: "0.0005"},
+		{name: "currency spread wins over type spread", from: usd, to: btc, want: "0.002"},
+		{name: "larger of both currency spreads", from: btc, to: eth, want: "0.003"},
+		{name: "type spread", from: usd, to: Currency{Name: "SOL", Type: Crypto}, want: "0.01"},
+		{name: "no spread", from: usd, to: eur, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spreads.MarkupFor(tt.from, tt.to); !got.Equal(dec(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+type currencyKey struct {
+	name string
+	at   time.Time
+}
+
+// GetRates converts every rate independently, a failed conversion does not affect the others.
+// Every distinct currency is loaded once for the whole batch.
+func (c currency) GetRates(ctx context.Context, rates []domain.Rate) ([]domain.ConversionResult, error) {
+	currencies, err := c.loadCurrencies(ctx, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := c.loadConversionRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.ConversionResult, 0, len(rates))
+
+	for _, rate := range rates {
+		var result domain.ConversionResult
+
+		currencyFrom, fromErr := currencies.get(rate.From, rate.At)
+		currencyTo, toErr := currencies.get(rate.To, rate.At)
+
+		switch {
+		case fromErr != nil:
+			result.Err = fromErr
+		case toErr != nil:
+			result.Err = toErr
+		default:
+			result.Conversion, result.Err = c.convert(rate, currencyFrom, currencyTo, rules)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+type loadedCurrency struct {
+	currency domain.Currency
+	err      error
+}
+
+type loadedCurrencies map[currencyKey]loadedCurrency
+
+func (l loadedCurrencies) get(name string, at time.Time) (domain.Currency, error) {
+	loaded, ok := l[currencyKey{name: name, at: at}]
+	if !ok {
+		return domain.Currency{}, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return loaded.currency, loaded.err
+}
+
+// loadCurrencies loads current currencies with one query and historical ones once per distinct instant and name.
+// Client errors, such as a missing rate at the requested instant, are kept and reported per item.
+func (c currency) loadCurrencies(ctx context.Context, rates []domain.Rate) (loadedCurrencies, error) {
+	var (
+		currentNames []string
+		historical   []currencyKey
+		seen         = make(map[currencyKey]bool)
+	)
+
+	for _, rate := range rates {
+		for _, name := range []string{rate.From, rate.To} {
+			key := currencyKey{name: name, at: rate.At}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if rate.At.IsZero() {
+				currentNames = append(currentNames, name)
+				continue
+			}
+
+			historical = append(historical, key)
+		}
+	}
+
+	loaded := make(loadedCurrencies, len(seen))
+
+	if len(currentNames) > 0 {
+		currencies, err := c.CurrencyRepo.GetCurrenciesByNames(ctx, currentNames)
+		if err != nil {
+			return nil, fmt.Errorf("get currencies by names: %w", err)
+		}
+
+		for _, currency := range currencies {
+			loaded[currencyKey{name: currency.Name}] = loadedCurrency{currency: currency}
+		}
+	}
+
+	for _, key := range historical {
+		currency, err := c.CurrencyRepo.GetCurrencyAt(ctx, key.name, key.at)
+		if err != nil && !isClientError(err) {
+			return nil, fmt.Errorf("get currency %s at %s: %w", key.name, key.at, err)
+		}
+
+		loaded[key] = loadedCurrency{currency: currency, err: err}
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func TestGetRatesReportsErrorsPerItem(t *testing.T) {
+	c := newConversionCurrency(nil, []domain.FeeSchedule{{From: "USD", To: "EUR", Fixed: dec("1")}})
+
+	results, err := c.GetRates(context.Background(), []domain.Rate{
+		{From: "USD", To: "EUR", Value: dec("100")},
+		{From: "USD", To: "EUR", Value: dec("1")},
+		{From: "USD", To: "XYZ", Value: dec("100")},
+		{From: "EUR", To: "USD", Value: dec("10")},
+	})
+	if err != nil {
+		t.Fatalf("get rates: %v", err)
+	}
+
+	want := []struct {
+		net string
+		err string
+	}{
+		{net: "49"},
+		{err: domain.ErrAmountBelowFee},
+		{err: domain.ErrNothingFound},
+		{net: "20"},
+	}
+
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+
+	for i, result := range results {
+		if want[i].err != "" {
+			if msg := serviceErrMessage(result.Err); msg != want[i].err {
+				t.Errorf("item %d: got error %v, want %s", i, result.Err, want[i].err)
+			}
+
+			continue
+		}
+
+		if result.Err != nil {
+			t.Errorf("item %d: %v", i, result.Err)
+			continue
+		}
+
+		if !result.Conversion.Net.Equal(dec(want[i].net)) {
+			t.Errorf("item %d: got net %s, want %s", i, result.Conversion.Net, want[i].net)
+		}
+	}
+}
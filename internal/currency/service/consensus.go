@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/shopspring/decimal"
+)
+
+// fetchConsensusMulti asks every provider of the chain for the currencies in parallel and
+// keeps the consensus of their quotes. It has the same contract as fetchMulti.
+func (c currency) fetchConsensusMulti(
+	ctx context.Context,
+	chain []string,
+	currencies []domain.Currency,
+	disabled disabledProviders,
+) ([]domain.CurrencyWithValue, []domain.Currency, error) {
+	responses := make([][]domain.CurrencyWithValue, len(chain))
+	errs := make([]error, len(chain))
+
+	var wg sync.WaitGroup
+
+	for idx, name := range chain {
+		if err := disabled.check(name); err != nil {
+			errs[idx] = err
+			continue
+		}
+
+		api, _ := c.Providers.Get(name)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp, err := api.SendMultiFetchRequest(ctx, currencies)
+			if err != nil {
+				c.Logger.Error().Err(err).Msgf("send multi fetch request, provider:%s, currencies:%d", name, len(currencies))
+				errs[idx] = fmt.Errorf("%s: %w", name, err)
+
+				return
+			}
+
+			responses[idx] = resp
+		}()
+	}
+
+	wg.Wait()
+
+	unlisted := true
+
+	for idx, err := range errs {
+		if err != nil {
+			disabled.record(chain[idx], err)
+			unlisted = unlisted && isUnknownSymbol(err)
+		}
+	}
+
+	quotes := make(map[string][]domain.ProviderQuote, len(currencies))
+
+	for idx, resp := range responses {
+		for _, value := range resp {
+			quotes[value.Name] = append(quotes[value.Name], domain.ProviderQuote{
+				Provider:  chain[idx],
+				Value:     value.Value,
+				FetchedAt: value.FetchedAt,
+			})
+		}
+	}
+
+	var (
+		values  []domain.CurrencyWithValue
+		missing []domain.Currency
+	)
+
+	for _, currency := range currencies {
+		value, ok := c.consensus(currency.Name, quotes[currency.Name], len(chain))
+		if !ok {
+			missing = append(missing, currency)
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	if len(missing) == 0 {
+		return values, nil, nil
+	}
+
+	// Only the currencies nobody quoted can be unlisted, the others were quoted but not agreed on.
+	var unquoted, disagreed []domain.Currency
+	for _, currency := range missing {
+		if len(quotes[currency.Name]) == 0 {
+			unquoted = append(unquoted, currency)
+		} else {
+			disagreed = append(disagreed, currency)
+		}
+	}
+
+	if unlisted && len(disagreed) == 0 {
+		return values, missing, newNotListedErr(unquoted)
+	}
+
+	return values, missing, errors.Join(append(errs, fmt.Errorf("no consensus: %s", joinNames(missing)))...)
+}
+
+// fetchConsensusOne asks every provider of the chain for the currency in parallel and returns the consensus of their quotes.
+// It has the same contract as fetchOne.
+func (c currency) fetchConsensusOne(
+	ctx context.Context,
+	chain []string,
+	currency domain.Currency,
+	disabled disabledProviders,
+) (domain.CurrencyWithValue, error) {
+	quotes := make([]*domain.ProviderQuote, len(chain))
+	errs := make([]error, len(chain))
+
+	var wg sync.WaitGroup
+
+	for idx, name := range chain {
+		if err := disabled.check(name); err != nil {
+			errs[idx] = err
+			continue
+		}
+
+		api, _ := c.Providers.Get(name)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp, err := api.SendFetchOneRequest(ctx, currency)
+			if err != nil {
+				c.Logger.Error().Err(err).Msgf("send fetch one request, name:%s, provider:%s", currency.Name, name)
+				errs[idx] = fmt.Errorf("%s: %w", name, err)
+
+				return
+			}
+
+			quotes[idx] = &domain.ProviderQuote{Provider: name, Value: resp.Value, FetchedAt: resp.FetchedAt}
+		}()
+	}
+
+	wg.Wait()
+
+	unlisted := true
+	received := make([]domain.ProviderQuote, 0, len(quotes))
+
+	for idx, quote := range quotes {
+		if quote != nil {
+			received = append(received, *quote)
+			unlisted = false
+		}
+
+		if errs[idx] != nil {
+			disabled.record(chain[idx], errs[idx])
+			unlisted = unlisted && isUnknownSymbol(errs[idx])
+		}
+	}
+
+	value, ok := c.consensus(currency.Name, received, len(chain))
+	if ok {
+		return value, nil
+	}
+
+	if unlisted {
+		return domain.CurrencyWithValue{}, newNotListedErr([]domain.Currency{currency})
+	}
+
+	return domain.CurrencyWithValue{}, errors.Join(append(errs, fmt.Errorf("no consensus, quotes:%d", len(received)))...)
+}
+
+// consensus builds the value of the currency from the quotes. The providers whose quotes
+// were accepted are recorded as its provider and the oldest of them dates the value.
+func (c currency) consensus(name string, quotes []domain.ProviderQuote, queried int) (domain.CurrencyWithValue, bool) {
+	consensus, ok := domain.BuildConsensus(quotes, c.Providers.Aggregation.Tolerance, queried)
+	if !ok {
+		return domain.CurrencyWithValue{}, false
+	}
+
+	for _, quote := range consensus.Rejected {
+		c.Logger.Warn().Msgf("quote rejected as outlier, name:%s, provider:%s, value:%s, consensus:%s",
+			name, quote.Provider, quote.Value, consensus.Value)
+	}
+
+	providers := make([]string, 0, len(consensus.Accepted))
+	fetchedAt := time.Now().UTC()
+
+	for _, quote := range consensus.Accepted {
+		providers = append(providers, quote.Provider)
+
+		if !quote.FetchedAt.IsZero() && quote.FetchedAt.Before(fetchedAt) {
+			fetchedAt = quote.FetchedAt
+		}
+	}
+
+	return domain.CurrencyWithValue{
+		Name:       name,
+		Value:      consensus.Value,
+		Provider:   strings.Join(providers, ","),
+		FetchedAt:  fetchedAt,
+		Confidence: decimal.NewNullDecimal(consensus.Confidence),
+	}, true
+}
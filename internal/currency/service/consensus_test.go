@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// fakeProvider quotes the currencies of values, like the adapters it reports an unknown symbol
+// when none of the requested currencies is quoted.
+type fakeProvider struct {
+	ForexAPI
+	name   string
+	values map[string]string
+	err    error
+}
+
+func (f fakeProvider) SendMultiFetchRequest(_ context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var resp []domain.CurrencyWithValue
+	for _, currency := range currencies {
+		if value, ok := f.values[currency.Name]; ok {
+			resp = append(resp, domain.CurrencyWithValue{Name: currency.Name, Value: dec(value), Provider: f.name})
+		}
+	}
+
+	if len(resp) == 0 {
+		return nil, domain.NewProviderError(f.name, domain.ProviderUnknownSymbol, 0, "no requested currency in response")
+	}
+
+	return resp, nil
+}
+
+func newConsensusCurrency(t *testing.T, providers ...fakeProvider) (*currency, []string) {
+	t.Helper()
+
+	registry := NewProviderRegistry(providers[0].name, Aggregation{Consensus: true, Tolerance: dec("0.01")}, domain.QuotaPolicy{})
+
+	chain := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		if err := registry.Register(provider.name, provider, ProviderOptions{}); err != nil {
+			t.Fatalf("register %s: %v", provider.name, err)
+		}
+
+		chain = append(chain, provider.name)
+	}
+
+	nop := zerolog.Nop()
+
+	return newCurrency(Repos{}, registry, domain.HalfEven, &nop), chain
+}
+
+func TestFetchConsensusMulti(t *testing.T) {
+	unknown := func(name string) error {
+		return domain.NewProviderError(name, domain.ProviderUnknownSymbol, 0, "no requested currency in response")
+	}
+
+	tests := []struct {
+		name         string
+		providers    []fakeProvider
+		wantValues   map[string]string
+		wantMissing  string
+		wantUnlisted bool
+		wantErr      bool
+	}{
+		{
+			name: "agreeing quotes",
+			providers: []fakeProvider{
+				{name: "a", values: map[string]string{"EUR": "0.9", "GBP": "0.8"}},
+				{name: "b", values: map[string]string{"EUR": "0.9", "GBP": "0.8"}},
+			},
+			wantValues: map[string]string{"EUR": "0.9", "GBP": "0.8"},
+		},
+		{
+			name: "currency quoted by one provider only",
+			providers: []fakeProvider{
+				{name: "a", values: map[string]string{"EUR": "0.9", "GBP": "0.8"}},
+				{name: "b", values: map[string]string{"EUR": "0.9"}},
+			},
+			wantValues: map[string]string{"EUR": "0.9", "GBP": "0.8"},
+		},
+		{
+			name: "nobody lists the currencies",
+			providers: []fakeProvider{
+				{name: "a", err: unknown("a")},
+				{name: "b", err: unknown("b")},
+			},
+			wantMissing:  "EUR,GBP",
+			wantUnlisted: true,
+			wantErr:      true,
+		},
+		{
+			name: "currency nobody quotes is not listed",
+			providers: []fakeProvider{
+				{name: "a", values: map[string]string{"EUR": "0.9"}},
+				{name: "b", err: unknown("b")},
+			},
+			wantValues:   map[string]string{"EUR": "0.9"},
+			wantMissing:  "GBP",
+			wantUnlisted: true,
+			wantErr:      true,
+		},
+		{
+			name: "disagreeing quotes are not unlisted",
+			providers: []fakeProvider{
+				{name: "a", values: map[string]string{"EUR": "0.9", "GBP": "0.8"}},
+				{name: "b", values: map[string]string{"EUR": "1.2", "GBP": "0.8"}},
+			},
+			wantValues:  map[string]string{"GBP": "0.8"},
+			wantMissing: "EUR",
+			wantErr:     true,
+		},
+		{
+			name: "unquoted currency along a disagreement is not unlisted",
+			providers: []fakeProvider{
+				{name: "a", values: map[string]string{"EUR": "0.9"}},
+				{name: "b", values: map[string]string{"EUR": "1.2"}},
+			},
+			wantMissing: "EUR,GBP",
+			wantErr:     true,
+		},
+		{
+			name: "failing provider is not an unknown symbol",
+			providers: []fakeProvider{
+				{name: "a", err: domain.NewProviderError("a", domain.ProviderUpstream, 502, "bad gateway")},
+				{name: "b", err: unknown("b")},
+			},
+			wantMissing: "EUR,GBP",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, chain := newConsensusCurrency(t, tt.providers...)
+
+			values, missing, err := c.fetchConsensusMulti(context.Background(), chain,
+				[]domain.Currency{{Name: "EUR"}, {Name: "GBP"}}, make(disabledProviders))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if unlisted := errors.Is(err, errNotListed); unlisted != tt.wantUnlisted {
+				t.Errorf("got %v, want unlisted %v", err, tt.wantUnlisted)
+			}
+
+			if tt.wantErr && !tt.wantUnlisted && !strings.Contains(err.Error(), "no consensus: "+tt.wantMissing) {
+				t.Errorf("got %v, want no consensus on %s", err, tt.wantMissing)
+			}
+
+			if got := joinNames(missing); got != tt.wantMissing {
+				t.Errorf("got missing %s, want %s", got, tt.wantMissing)
+			}
+
+			if len(values) != len(tt.wantValues) {
+				t.Fatalf("got %d values, want %d", len(values), len(tt.wantValues))
+			}
+
+			for _, value := range values {
+				want, ok := tt.wantValues[value.Name]
+				if !ok || !value.Value.Equal(decimal.RequireFromString(want)) {
+					t.Errorf("got %s %s, want %s", value.Name, value.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchConsensusMultiDisablesProvider(t *testing.T) {
+	c, chain := newConsensusCurrency(t,
+		fakeProvider{name: "a", err: domain.NewProviderError("a", domain.ProviderUnauthorized, 401, "invalid key")},
+		fakeProvider{name: "b", values: map[string]string{"EUR": "0.9"}},
+	)
+
+	disabled := make(disabledProviders)
+
+	values, missing, err := c.fetchConsensusMulti(context.Background(), chain, []domain.Currency{{Name: "EUR"}}, disabled)
+	if err != nil || len(missing) != 0 || len(values) != 1 {
+		t.Fatalf("got %v, %v, %v, want the quote of b", values, missing, err)
+	}
+
+	if values[0].Provider != "b" {
+		t.Errorf("got provider %s, want b", values[0].Provider)
+	}
+
+	if _, ok := disabled["a"]; !ok {
+		t.Error("provider rejecting the credentials is not disabled")
+	}
+}
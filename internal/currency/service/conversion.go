@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/shopspring/decimal"
+)
+
+// divisionGuardDigits are kept beyond the target precision before the final rounding.
+const divisionGuardDigits = 16
+
+// conversionRules are the admin managed rules applied to every conversion.
+type conversionRules struct {
+	policies domain.PairPolicies
+	spreads  domain.Spreads
+	fees     domain.FeeSchedules
+}
+
+func (c currency) loadConversionRules(ctx context.Context) (conversionRules, error) {
+	policies, err := c.PairPolicyRepo.GetPairPolicies(ctx)
+	if err != nil {
+		return conversionRules{}, fmt.Errorf("get pair policies: %w", err)
+	}
+
+	spreads, err := c.SpreadRepo.GetSpreads(ctx)
+	if err != nil {
+		return conversionRules{}, fmt.Errorf("get spreads: %w", err)
+	}
+
+	fees, err := c.FeeRepo.GetFeeSchedules(ctx)
+	if err != nil {
+		return conversionRules{}, fmt.Errorf("get fee schedules: %w", err)
+	}
+
+	return conversionRules{
+		policies: policies,
+		spreads:  spreads,
+		fees:     fees,
+	}, nil
+}
+
+// convert applies the conversion rules and converts the value between already loaded currencies.
+func (c currency) convert(rate domain.Rate, currencyFrom, currencyTo domain.Currency, rules conversionRules) (domain.Conversion, error) {
+	if !rules.policies.IsAllowed(currencyFrom, currencyTo) {
+		return domain.Conversion{}, domain.NewServiceError(domain.ErrPairNotAllowed, domain.Client)
+	}
+
+	if !currencyFrom.IsAvailable || !currencyTo.IsAvailable {
+		return domain.Conversion{}, domain.NewServiceError(domain.ErrCurrencyUnavailable, domain.Client)
+	}
+
+	if decimal.Zero.Equal(currencyFrom.ValueUSD) || decimal.Zero.Equal(currencyTo.ValueUSD) {
+		return domain.Conversion{}, domain.NewServiceError(domain.ErrValueCannotBeZero, domain.Client)
+	}
+
+	rounding := rate.Rounding
+	if rounding == "" {
+		rounding = c.DefaultRounding
+	}
+
+	places := currencyTo.Precision + divisionGuardDigits
+	markup := rules.spreads.MarkupFor(currencyFrom, currencyTo)
+
+	// The value of one unit of the currency is its dollar equivalent of the currency to exchange divided by its own dollar equivalent
+	mid := currencyTo.ValueUSD.DivRound(currencyFrom.ValueUSD, places)
+	bid := mid.Mul(decimal.NewFromInt(1).Sub(markup)).Round(places)
+	ask := mid.Mul(decimal.NewFromInt(1).Add(markup)).Round(places)
+
+	// The customer sells the source currency, so the value is converted at the bid
+	rateValue := rate.Value.Mul(currencyTo.ValueUSD).Mul(decimal.NewFromInt(1).Sub(markup)).DivRound(currencyFrom.ValueUSD, places)
+
+	gross := rounding.Round(rateValue, currencyTo.Precision)
+
+	// Fees are charged after the rate, on the gross amount in the target currency
+	var fee domain.Fee
+	if schedule, ok := rules.fees.For(currencyFrom.Name, currencyTo.Name); ok {
+		fee = schedule.Apply(gross, currencyTo.Precision, rounding)
+	}
+
+	net := gross.Sub(fee.Total)
+	if net.IsNegative() {
+		return domain.Conversion{}, domain.NewServiceError(domain.ErrAmountBelowFee, domain.Client)
+	}
+
+	return domain.Conversion{
+		Amount:    gross,
+		Fee:       fee,
+		Net:       net,
+		Precision: currencyTo.Precision,
+		Rounding:  rounding,
+		Mid:       mid,
+		Bid:       bid,
+		Ask:       ask,
+		Markup:    markup,
+	}, nil
+}
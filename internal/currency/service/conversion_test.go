@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+type fakeCurrencyRepo struct {
+	CurrencyRepo
+	currencies map[string]domain.Currency
+}
+
+func (f fakeCurrencyRepo) GetCurrency(_ context.Context, name string) (domain.Currency, error) {
+	currency, ok := f.currencies[name]
+	if !ok {
+		return domain.Currency{}, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	return currency, nil
+}
+
+func (f fakeCurrencyRepo) GetCurrenciesByNames(_ context.Context, names []string) ([]domain.Currency, error) {
+	currencies := make([]domain.Currency, 0, len(names))
+	for _, name := range names {
+		if currency, ok := f.currencies[name]; ok {
+			currencies = append(currencies, currency)
+		}
+	}
+
+	return currencies, nil
+}
+
+type fakePairPolicyRepo struct {
+	PairPolicyRepo
+	policies []domain.PairPolicy
+}
+
+func (f fakePairPolicyRepo) GetPairPolicies(context.Context) ([]domain.PairPolicy, error) {
+	return f.policies, nil
+}
+
+type fakeSpreadRepo struct {
+	SpreadRepo
+	spreads []domain.Spread
+}
+
+func (f fakeSpreadRepo) GetSpreads(context.Context) ([]domain.Spread, error) {
+	return f.spreads, nil
+}
+
+type fakeFeeRepo struct {
+	FeeRepo
+	schedules []domain.FeeSchedule
+}
+
+func (f fakeFeeRepo) GetFeeSchedules(context.Context) ([]domain.FeeSchedule, error) {
+	return f.schedules, nil
+}
+
+// newConversionCurrency returns the service converting between USD and EUR, worth 0.5 EUR per USD.
+func newConversionCurrency(spreads []domain.Spread, fees []domain.FeeSchedule) *currency {
+	nop := zerolog.Nop()
+
+	return newCurrency(Repos{
+		CurrencyRepo: fakeCurrencyRepo{currencies: map[string]domain.Currency{
+			"USD": {Name: "USD", Type: domain.Fiat, ValueUSD: dec("1"), IsAvailable: true, Precision: 2},
+			"EUR": {Name: "EUR", Type: domain.Fiat, ValueUSD: dec("0.5"), IsAvailable: true, Precision: 2},
+		}},
+		PairPolicyRepo: fakePairPolicyRepo{policies: []domain.PairPolicy{
+			{FromType: domain.Fiat, ToType: domain.Fiat, IsAllowed: true},
+		}},
+		SpreadRepo: fakeSpreadRepo{spreads: spreads},
+		FeeRepo:    fakeFeeRepo{schedules: fees},
+	}, nil, domain.HalfEven, &nop)
+}
+
+func serviceErrMessage(err error) string {
+	var serviceErr *domain.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Message
+	}
+
+	return ""
+}
+
+func TestGetRateFees(t *testing.T) {
+	usdEUR := domain.FeeSchedule{From: "USD", To: "EUR", Fixed: dec("1"), Percentage: dec("0.01")}
+
+	tests := []struct {
+		name    string
+		value   string
+		spreads []domain.Spread
+		fees    []domain.FeeSchedule
+		want    domain.Conversion
+		wantErr string
+	}{
+		{
+			name:  "no fee schedule",
+			value: "100",
+			want: domain.Conversion{
+				Amount: dec("50"), Net: dec("50"),
+				Mid: dec("0.5"), Bid: dec("0.5"), Ask: dec("0.5"), Markup: dec("0"),
+			},
+		},
+		{
+			name:  "fee is taken from the gross amount",
+			value: "100",
+			fees:  []domain.FeeSchedule{usdEUR},
+			want: domain.Conversion{
+				Amount: dec("50"), Net: dec("48.5"),
+				Fee: domain.Fee{Fixed: dec("1"), Percentage: dec("0.5"), CapAdjustment: dec("0"), Total: dec("1.5")},
+				Mid: dec("0.5"), Bid: dec("0.5"), Ask: dec("0.5"), Markup: dec("0"),
+			},
+		},
+		{
+			name:  "fee of the other direction is not applied",
+			value: "100",
+			fees:  []domain.FeeSchedule{{From: "EUR", To: "USD", Fixed: dec("1")}},
+			want: domain.Conversion{
+				Amount: dec("50"), Net: dec("50"),
+				Mid: dec("0.5"), Bid: dec("0.5"), Ask: dec("0.5"), Markup: dec("0"),
+			},
+		},
+		{
+			name:    "fee is charged after the spread",
+			value:   "100",
+			spreads: []domain.Spread{{From: "USD", To: "EUR", Markup: dec("0.02")}},
+			fees:    []domain.FeeSchedule{{From: "USD", To: "EUR", Percentage: dec("0.1")}},
+			want: domain.Conversion{
+				Amount: dec("49"), Net: dec("44.1"),
+				Fee: domain.Fee{Fixed: dec("0"), Percentage: dec("4.9"), CapAdjustment: dec("0"), Total: dec("4.9")},
+				Mid: dec("0.5"), Bid: dec("0.49"), Ask: dec("0.51"), Markup: dec("0.02"),
+			},
+		},
+		{
+			name:  "fee equal to the amount leaves nothing",
+			value: "2",
+			fees:  []domain.FeeSchedule{{From: "USD", To: "EUR", Fixed: dec("1")}},
+			want: domain.Conversion{
+				Amount: dec("1"), Net: dec("0"),
+				Fee: domain.Fee{Fixed: dec("1"), Percentage: dec("0"), CapAdjustment: dec("0"), Total: dec("1")},
+				Mid: dec("0.5"), Bid: dec("0.5"), Ask: dec("0.5"), Markup: dec("0"),
+			},
+		},
+		{
+			name:    "amount below the fee",
+			value:   "1",
+			fees:    []domain.FeeSchedule{usdEUR},
+			wantErr: domain.ErrAmountBelowFee,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConversionCurrency(tt.spreads, tt.fees)
+
+			got, err := c.GetRate(context.Background(), domain.Rate{From: "USD", To: "EUR", Value: dec(tt.value)})
+			if tt.wantErr != "" {
+				if msg := serviceErrMessage(err); msg != tt.wantErr {
+					t.Fatalf("got error %v, want %s", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("get rate: %v", err)
+			}
+
+			if !got.Amount.Equal(tt.want.Amount) || !got.Net.Equal(tt.want.Net) {
+				t.Errorf("got amount %s net %s, want %s net %s", got.Amount, got.Net, tt.want.Amount, tt.want.Net)
+			}
+
+			if !got.Fee.Fixed.Equal(tt.want.Fee.Fixed) || !got.Fee.Percentage.Equal(tt.want.Fee.Percentage) ||
+				!got.Fee.CapAdjustment.Equal(tt.want.Fee.CapAdjustment) || !got.Fee.Total.Equal(tt.want.Fee.Total) {
+				t.Errorf("got fee %+v, want %+v", got.Fee, tt.want.Fee)
+			}
+
+			if !got.Mid.Equal(tt.want.Mid) || !got.Bid.Equal(tt.want.Bid) || !got.Ask.Equal(tt.want.Ask) || !got.Markup.Equal(tt.want.Markup) {
+				t.Errorf("got mid %s bid %s ask %s markup %s, want %s %s %s %s",
+					got.Mid, got.Bid, got.Ask, got.Markup, tt.want.Mid, tt.want.Bid, tt.want.Ask, tt.want.Markup)
+			}
+
+			if got.Precision != 2 || got.Rounding != domain.HalfEven {
+				t.Errorf("got precision %d rounding %s, want 2 %s", got.Precision, got.Rounding, domain.HalfEven)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/alemax1/currencies-api/internal/currency/domain"
 	"github.com/alemax1/currencies-api/pkg/logger"
@@ -12,32 +14,98 @@ import (
 type ForexAPI interface {
 	SendFetchOneRequest(ctx context.Context, currency domain.Currency) (domain.CurrencyWithValue, error)
 	SendMultiFetchRequest(ctx context.Context, currencies []domain.Currency) ([]domain.CurrencyWithValue, error)
+	SendTimeSeriesRequest(ctx context.Context, currency domain.Currency, from, to time.Time) ([]domain.CurrencyWithValue, error)
 }
 
 type CurrencyRepo interface {
 	AddEmptyCurrency(ctx context.Context, currency domain.Currency) (int64, error)
 	GetCurrency(ctx context.Context, name string) (domain.Currency, error)
+	GetCurrencyAt(ctx context.Context, name string, at time.Time) (domain.Currency, error)
+	GetCurrenciesByNames(ctx context.Context, names []string) ([]domain.Currency, error)
 	UpdateCurrencyAvailability(ctx context.Context, name string, isAvailable bool) error
+	UpdateCurrencyPriority(ctx context.Context, name string, priority int32) error
 	GetAll(ctx context.Context) ([]domain.Currency, error)
 	GetCurrenciesByType(ctx context.Context, tp domain.CurrencyType) ([]domain.Currency, error)
 	UpdateCurrencyByName(ctx context.Context, currency domain.CurrencyUpdateData) error
 }
 
+type HistoryRepo interface {
+	AddObservations(ctx context.Context, currencyID int64, observations []domain.CurrencyWithValue) (int64, error)
+	GetBars(ctx context.Context, currencyID int64, from, to time.Time) ([]domain.RateBar, error)
+	Downsample(ctx context.Context, currencyID int64, resolution domain.Resolution, from, to time.Time) (int64, error)
+	PurgeObservations(ctx context.Context, before time.Time) (int64, error)
+	PurgeAggregates(ctx context.Context, resolution domain.Resolution, before time.Time) (int64, error)
+}
+
+type PairPolicyRepo interface {
+	GetPairPolicies(ctx context.Context) ([]domain.PairPolicy, error)
+	SavePairPolicy(ctx context.Context, policy domain.PairPolicy) (int64, error)
+	DeletePairPolicy(ctx context.Context, id int64) error
+}
+
+type SpreadRepo interface {
+	GetSpreads(ctx context.Context) ([]domain.Spread, error)
+	SaveSpread(ctx context.Context, spread domain.Spread) (int64, error)
+	DeleteSpread(ctx context.Context, id int64) error
+}
+
+type FeeRepo interface {
+	GetFeeSchedules(ctx context.Context) ([]domain.FeeSchedule, error)
+	SaveFeeSchedule(ctx context.Context, schedule domain.FeeSchedule) (int64, error)
+	DeleteFeeSchedule(ctx context.Context, id int64) error
+}
+
+type OverrideRepo interface {
+	GetOverrides(ctx context.Context) ([]domain.RateOverride, error)
+	SetOverride(ctx context.Context, override domain.RateOverride) error
+	ClearOverride(ctx context.Context, name, actor, reason string) error
+	GetOverrideAudit(ctx context.Context, name string) ([]domain.OverrideAuditEntry, error)
+}
+
+type ProviderHealthRepo interface {
+	SaveProviderHealth(ctx context.Context, health []domain.ProviderHealth) error
+	GetProviderHealth(ctx context.Context) ([]domain.ProviderHealth, error)
+}
+
+type QuotaRepo interface {
+	AddCalls(ctx context.Context, day time.Time, calls map[string]int64) error
+	GetUsage(ctx context.Context, day time.Time) ([]domain.QuotaUsage, error)
+}
+
+type SymbolRepo interface {
+	GetProviderSymbols(ctx context.Context, provider string) ([]domain.ProviderSymbol, error)
+	SetProviderSymbol(ctx context.Context, symbol domain.ProviderSymbol) error
+	DeleteProviderSymbol(ctx context.Context, provider, name string) error
+}
+
+type PegRepo interface {
+	GetPegs(ctx context.Context) ([]domain.Peg, error)
+	SetPeg(ctx context.Context, peg domain.Peg) error
+	ClearPeg(ctx context.Context, name string) error
+	OpenDepegEvent(ctx context.Context, peg domain.Peg) error
+	UpdateDepegEvent(ctx context.Context, name string, deviation decimal.Decimal) error
+	CloseDepegEvent(ctx context.Context, name string) error
+	GetDepegEvents(ctx context.Context, name string) ([]domain.DepegEvent, error)
+}
+
 type currency struct {
-	CurrencyRepo CurrencyRepo
-	ForexAPI     ForexAPI
-	Logger       logger.Logger
+	Repos
+	Providers       *ProviderRegistry
+	DefaultRounding domain.RoundingMode
+	Logger          logger.Logger
 }
 
 func newCurrency(
-	currencyRepo CurrencyRepo,
-	forexAPI ForexAPI,
+	repos Repos,
+	providers *ProviderRegistry,
+	defaultRounding domain.RoundingMode,
 	logger logger.Logger,
 ) *currency {
 	return &currency{
-		CurrencyRepo: currencyRepo,
-		ForexAPI:     forexAPI,
-		Logger:       logger,
+		Repos:           repos,
+		Providers:       providers,
+		DefaultRounding: defaultRounding,
+		Logger:          logger,
 	}
 }
 
@@ -50,33 +118,66 @@ func (c currency) Create(ctx context.Context, currency domain.Currency) (int64,
 	return id, nil
 }
 
-func (c currency) GetRate(ctx context.Context, rate domain.Rate) (float64, error) {
-	currencyFrom, err := c.CurrencyRepo.GetCurrency(ctx, rate.From)
+func (c currency) GetRate(ctx context.Context, rate domain.Rate) (domain.Conversion, error) {
+	currencyFrom, err := c.getCurrency(ctx, rate.From, rate.At)
+	if err != nil {
+		return domain.Conversion{}, fmt.Errorf("get from currency: %w", err)
+	}
+
+	currencyTo, err := c.getCurrency(ctx, rate.To, rate.At)
 	if err != nil {
-		return 0, fmt.Errorf("get from currency: %w", err)
+		return domain.Conversion{}, fmt.Errorf("get to currency: %w", err)
+	}
+
+	rules, err := c.loadConversionRules(ctx)
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+
+	return c.convert(rate, currencyFrom, currencyTo, rules)
+}
+
+const maxCandles = 1000
+
+func (c currency) GetCandles(ctx context.Context, req domain.CandlesRequest) ([]domain.Candle, error) {
+	if req.Name == req.Quote {
+		return nil, domain.NewServiceError(domain.ErrEqualCurrencies, domain.Client)
+	}
+
+	if req.To.Sub(req.From)/req.Interval > maxCandles {
+		return nil, domain.NewServiceError(domain.ErrTooManyCandles, domain.Client)
 	}
 
-	currencyTo, err := c.CurrencyRepo.GetCurrency(ctx, rate.To)
+	base, err := c.CurrencyRepo.GetCurrency(ctx, req.Name)
 	if err != nil {
-		return 0, fmt.Errorf("get to currency: %w", err)
+		return nil, fmt.Errorf("get base currency: %w", err)
 	}
 
-	if currencyFrom.Type == currencyTo.Type {
-		return 0, domain.NewServiceError(domain.ErrInvalidCurrencyTypes, domain.Client)
+	quote, err := c.CurrencyRepo.GetCurrency(ctx, req.Quote)
+	if err != nil {
+		return nil, fmt.Errorf("get quote currency: %w", err)
 	}
 
-	if !currencyFrom.IsAvailable || !currencyTo.IsAvailable {
-		return 0, domain.NewServiceError(domain.ErrInvalidCurrencyTypes, domain.Client)
+	baseBars, err := c.HistoryRepo.GetBars(ctx, base.ID, req.From, req.To)
+	if err != nil {
+		return nil, fmt.Errorf("get base bars: %w", err)
 	}
 
-	if decimal.Zero.Equal(currencyFrom.ValueUSD) || decimal.Zero.Equal(currencyTo.ValueUSD) {
-		return 0, domain.NewServiceError(domain.ErrValueCannotBeZero, domain.Client)
+	quoteBars, err := c.HistoryRepo.GetBars(ctx, quote.ID, req.From, req.To)
+	if err != nil {
+		return nil, fmt.Errorf("get quote bars: %w", err)
 	}
 
-	// The rate value of the currency is divided by its dollar equivalent and multiplied by the dollar equivalent of the currency to exchange
-	rateValue := rate.Value.Div(currencyFrom.ValueUSD).Mul(currencyTo.ValueUSD)
+	return domain.BuildCandles(baseBars, quoteBars, req.From, req.To, req.Interval), nil
+}
+
+// getCurrency returns the current state of the currency, or its historical value when at is set.
+func (c currency) getCurrency(ctx context.Context, name string, at time.Time) (domain.Currency, error) {
+	if at.IsZero() {
+		return c.CurrencyRepo.GetCurrency(ctx, name)
+	}
 
-	return rateValue.InexactFloat64(), nil
+	return c.CurrencyRepo.GetCurrencyAt(ctx, name, at)
 }
 
 func (c currency) ChangeAvailability(ctx context.Context, name string, isAvailable bool) error {
@@ -87,6 +188,14 @@ func (c currency) ChangeAvailability(ctx context.Context, name string, isAvailab
 	return nil
 }
 
+func (c currency) ChangePriority(ctx context.Context, name string, priority int32) error {
+	if err := c.CurrencyRepo.UpdateCurrencyPriority(ctx, name, priority); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c currency) GetAll(ctx context.Context) ([]domain.Currency, error) {
 	currencies, err := c.CurrencyRepo.GetAll(ctx)
 	if err != nil {
@@ -96,46 +205,86 @@ func (c currency) GetAll(ctx context.Context) ([]domain.Currency, error) {
 	return currencies, nil
 }
 
+// UpdateFiatCurrencies fetches the fiat currencies in one request per provider, falling back
+// to the next provider of the chain for the currencies a provider failed to supply,
+// or asking the whole chain at once for a consensus.
 func (c currency) UpdateFiatCurrencies(ctx context.Context) error {
 	currencies, err := c.CurrencyRepo.GetCurrenciesByType(ctx, domain.Fiat)
 	if err != nil {
 		return fmt.Errorf("get all currencies by type: %w", err)
 	}
 
-	resp, err := c.ForexAPI.SendMultiFetchRequest(ctx, currencies)
-	if err != nil || len(resp) == 0 {
-		return fmt.Errorf("send multi fetch request: %w", err)
+	fetch := c.fetchMulti
+	if c.Providers.Aggregation.Consensus {
+		fetch = c.fetchConsensusMulti
 	}
 
-	for _, currency := range resp {
-		currencyUpdate := domain.CurrencyUpdateData{
-			Name:        currency.Name,
-			ValueUSD:    currency.Value,
-			IsAvailable: true,
+	var errs []error
+
+	disabled := make(disabledProviders)
+	currencies = c.applyQuota(ctx, currencies, disabled)
+	defer c.recordCalls(ctx)
+
+	pegs := c.loadPegs(ctx)
+
+	for provider, group := range c.Providers.Group(currencies) {
+		resp, missing, err := fetch(ctx, c.Providers.ChainOf(provider), group, disabled)
+		switch {
+		case errors.Is(err, errNotListed):
+			c.markUnlisted(ctx, missing)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("fetch currencies of %s: %w", provider, err))
 		}
 
-		if err := c.CurrencyRepo.UpdateCurrencyByName(ctx, currencyUpdate); err != nil {
-			c.Logger.Error().Err(err).Msgf("update currency, value:%s, name:%s", currency.Value, currency.Name)
+		for _, currency := range resp {
+			currencyUpdate := domain.CurrencyUpdateData{
+				Name:        currency.Name,
+				ValueUSD:    currency.Value,
+				IsAvailable: true,
+				Provider:    currency.Provider,
+				FetchedAt:   currency.FetchedAt,
+				Confidence:  currency.Confidence,
+			}
 
-			if err := c.CurrencyRepo.UpdateCurrencyAvailability(ctx, currency.Name, false); err != nil {
-				c.Logger.Error().Err(err).Msgf("update currency availability name:%s", currency.Name)
+			if err := c.updateCurrency(ctx, pegs, currencyUpdate); err != nil {
+				c.Logger.Error().Err(err).Msgf("update currency, value:%s, name:%s", currency.Value, currency.Name)
+				c.markUnavailable(ctx, currency.Name)
 			}
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func (c currency) UpdateCryptoCurrencies(ctx context.Context) error {
-	currencies, err := c.CurrencyRepo.GetCurrenciesByType(context.Background(), domain.Crypto)
+	currencies, err := c.CurrencyRepo.GetCurrenciesByType(ctx, domain.Crypto)
 	if err != nil {
 		return fmt.Errorf("get currencies by type: %w", err)
 	}
 
+	fetch := c.fetchOne
+	if c.Providers.Aggregation.Consensus {
+		fetch = c.fetchConsensusOne
+	}
+
+	var errs []error
+
+	disabled := make(disabledProviders)
+	currencies = c.applyQuota(ctx, currencies, disabled)
+	defer c.recordCalls(ctx)
+
+	pegs := c.loadPegs(ctx)
+
 	for _, currency := range currencies {
-		resp, err := c.ForexAPI.SendFetchOneRequest(ctx, currency)
+		resp, err := fetch(ctx, c.Providers.Chain(currency), currency, disabled)
+		if errors.Is(err, errNotListed) {
+			c.markUnlisted(ctx, []domain.Currency{currency})
+			continue
+		}
 		if err != nil {
-			c.Logger.Error().Err(err).Msg("send fetch one request")
+			c.Logger.Error().Err(err).Msgf("fetch currency from every provider, name:%s", currency.Name)
+			errs = append(errs, fmt.Errorf("fetch %s: %w", currency.Name, err))
+
 			continue
 		}
 
@@ -143,16 +292,55 @@ func (c currency) UpdateCryptoCurrencies(ctx context.Context) error {
 			Name:        resp.Name,
 			ValueUSD:    resp.Value,
 			IsAvailable: true,
+			Provider:    resp.Provider,
+			FetchedAt:   resp.FetchedAt,
+			Confidence:  resp.Confidence,
 		}
 
-		if err := c.CurrencyRepo.UpdateCurrencyByName(context.Background(), currencyUpdate); err != nil {
+		if err := c.updateCurrency(ctx, pegs, currencyUpdate); err != nil {
 			c.Logger.Error().Err(err).Msgf("update currency, value:%s, name:%s", resp.Value, currency.Name)
-
-			if err := c.CurrencyRepo.UpdateCurrencyAvailability(ctx, currency.Name, false); err != nil {
-				c.Logger.Error().Err(err).Msgf("update currency availability name:%s", currency.Name)
-			}
+			c.markUnavailable(ctx, currency.Name)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// markUnlisted makes the currencies no provider knows unavailable instead of serving their
+// last value indefinitely. They become available again once a provider supplies them.
+func (c currency) markUnlisted(ctx context.Context, currencies []domain.Currency) {
+	names := make([]string, 0, len(currencies))
+
+	for _, currency := range currencies {
+		c.Logger.Warn().Msgf("currency is not listed by any provider, marking unavailable, name:%s", currency.Name)
+		names = append(names, currency.Name)
+	}
+
+	c.markUnavailable(ctx, names...)
+}
+
+// markUnavailable makes the currencies unavailable after a failed update. A currency with an active
+// override is left as it is: ops pinned its value, and it must not be gone once the override ends.
+func (c currency) markUnavailable(ctx context.Context, names ...string) {
+	overrides, err := c.OverrideRepo.GetOverrides(ctx)
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("get overrides")
+		return
+	}
+
+	overridden := make(map[string]bool, len(overrides))
+	for _, override := range overrides {
+		overridden[override.Currency] = true
+	}
+
+	for _, name := range names {
+		if overridden[name] {
+			c.Logger.Warn().Msgf("currency value is overridden, keeping availability, name:%s", name)
+			continue
+		}
+
+		if err := c.CurrencyRepo.UpdateCurrencyAvailability(ctx, name, false); err != nil {
+			c.Logger.Error().Err(err).Msgf("update currency availability name:%s", name)
+		}
+	}
 }
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func isClientError(err error) bool {
+	var serviceErr *domain.ServiceError
+
+	return errors.As(err, &serviceErr) && serviceErr.Type == domain.Client
+}
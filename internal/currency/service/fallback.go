@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// fetchMulti asks the providers of the chain in order for the currencies, each one only for
+// the currencies the previous ones failed to supply. It returns the fetched values, the
+// currencies no provider supplied and an error when some currencies were not supplied,
+// wrapping errNotListed when every provider answered without them.
+func (c currency) fetchMulti(
+	ctx context.Context,
+	chain []string,
+	currencies []domain.Currency,
+	disabled disabledProviders,
+) ([]domain.CurrencyWithValue, []domain.Currency, error) {
+	var (
+		values   []domain.CurrencyWithValue
+		errs     []error
+		pending  = currencies
+		unlisted = true
+	)
+
+	for idx, name := range chain {
+		if len(pending) == 0 {
+			break
+		}
+
+		if err := disabled.check(name); err != nil {
+			errs = append(errs, err)
+			unlisted = false
+
+			continue
+		}
+
+		api, _ := c.Providers.Get(name)
+
+		resp, err := api.SendMultiFetchRequest(ctx, pending)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("send multi fetch request, provider:%s, currencies:%d", name, len(pending))
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			disabled.record(name, err)
+			unlisted = unlisted && isUnknownSymbol(err)
+
+			continue
+		}
+
+		requested := make(map[string]bool, len(pending))
+		for _, currency := range pending {
+			requested[currency.Name] = true
+		}
+
+		for _, value := range resp {
+			if !requested[value.Name] {
+				continue
+			}
+
+			delete(requested, value.Name)
+			values = append(values, value)
+
+			if idx > 0 {
+				c.Logger.Warn().Msgf("rate supplied by fallback provider, name:%s, provider:%s, primary:%s", value.Name, name, chain[0])
+			}
+		}
+
+		missing := make([]domain.Currency, 0, len(requested))
+		for _, currency := range pending {
+			if requested[currency.Name] {
+				missing = append(missing, currency)
+			}
+		}
+
+		if len(missing) > 0 {
+			c.Logger.Warn().Msgf("provider did not supply every currency, provider:%s, missing:%d", name, len(missing))
+		}
+
+		pending = missing
+	}
+
+	if len(pending) == 0 {
+		return values, nil, nil
+	}
+
+	if unlisted {
+		return values, pending, newNotListedErr(pending)
+	}
+
+	errs = append(errs, fmt.Errorf("not supplied by any provider: %s", joinNames(pending)))
+
+	return values, pending, errors.Join(errs...)
+}
+
+// fetchOne asks the providers of the chain in order for the currency until one of them supplies it.
+// The error wraps errNotListed when every provider reported the currency as unknown.
+func (c currency) fetchOne(
+	ctx context.Context,
+	chain []string,
+	currency domain.Currency,
+	disabled disabledProviders,
+) (domain.CurrencyWithValue, error) {
+	var (
+		errs     []error
+		unlisted = true
+	)
+
+	for idx, name := range chain {
+		if err := disabled.check(name); err != nil {
+			errs = append(errs, err)
+			unlisted = false
+
+			continue
+		}
+
+		api, _ := c.Providers.Get(name)
+
+		resp, err := api.SendFetchOneRequest(ctx, currency)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("send fetch one request, name:%s, provider:%s", currency.Name, name)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			disabled.record(name, err)
+			unlisted = unlisted && isUnknownSymbol(err)
+
+			continue
+		}
+
+		if idx > 0 {
+			c.Logger.Warn().Msgf("rate supplied by fallback provider, name:%s, provider:%s, primary:%s", currency.Name, name, chain[0])
+		}
+
+		return resp, nil
+	}
+
+	if unlisted {
+		return domain.CurrencyWithValue{}, newNotListedErr([]domain.Currency{currency})
+	}
+
+	return domain.CurrencyWithValue{}, errors.Join(errs...)
+}
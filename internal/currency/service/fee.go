@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func (c currency) GetFeeSchedules(ctx context.Context) ([]domain.FeeSchedule, error) {
+	schedules, err := c.FeeRepo.GetFeeSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (c currency) SaveFeeSchedule(ctx context.Context, schedule domain.FeeSchedule) (int64, error) {
+	id, err := c.FeeRepo.SaveFeeSchedule(ctx, schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (c currency) DeleteFeeSchedule(ctx context.Context, id int64) error {
+	if err := c.FeeRepo.DeleteFeeSchedule(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// MaintainHistory downsamples and purges the rate history according to the policy, which
+// must be valid. It only touches observations older than policy.DownsampleAfter and every
+// statement is idempotent, so it is safe to run while currencies are being refreshed.
+func (c currency) MaintainHistory(ctx context.Context, policy domain.RetentionPolicy) error {
+	now := time.Now().UTC()
+
+	// Raw rows are purged at day boundaries, so every bucket downsampled from
+	// rawBoundary onwards is still built from complete data.
+	rawBoundary := now.Add(-policy.RawRetention).Truncate(domain.Day.Duration())
+
+	for _, resolution := range []domain.Resolution{domain.Hour, domain.Day} {
+		to := now.Add(-policy.DownsampleAfter).Truncate(resolution.Duration())
+
+		buckets, err := c.HistoryRepo.Downsample(ctx, 0, resolution, rawBoundary, to)
+		if err != nil {
+			return fmt.Errorf("downsample %s: %w", resolution, err)
+		}
+
+		c.Logger.Info().Msgf("downsampled history, resolution:%s, buckets:%d", resolution, buckets)
+	}
+
+	purged, err := c.HistoryRepo.PurgeObservations(ctx, rawBoundary)
+	if err != nil {
+		return fmt.Errorf("purge observations: %w", err)
+	}
+
+	c.Logger.Info().Msgf("purged raw history, rows:%d", purged)
+
+	hourlyBoundary := now.Add(-policy.HourlyRetention).Truncate(domain.Day.Duration())
+
+	purged, err = c.HistoryRepo.PurgeAggregates(ctx, domain.Hour, hourlyBoundary)
+	if err != nil {
+		return fmt.Errorf("purge hourly aggregates: %w", err)
+	}
+
+	c.Logger.Info().Msgf("purged hourly history, rows:%d", purged)
+
+	return nil
+}
+
+// backfillChunk limits the range requested from the provider in one time series call.
+const backfillChunk = 90 * 24 * time.Hour
+
+// Backfill loads daily rates of the currency for every day in [from, to] into history
+// without changing its current value. Days that already have history are left intact.
+func (c currency) Backfill(ctx context.Context, name string, from, to time.Time) (int64, error) {
+	currency, err := c.CurrencyRepo.GetCurrency(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("get currency: %w", err)
+	}
+
+	c.limitCalls(ctx)
+	defer c.recordCalls(ctx)
+
+	from = from.UTC().Truncate(domain.Day.Duration())
+	to = to.UTC().Truncate(domain.Day.Duration())
+
+	var inserted int64
+
+	for chunkFrom := from; !chunkFrom.After(to); chunkFrom = chunkFrom.Add(backfillChunk) {
+		chunkTo := chunkFrom.Add(backfillChunk - domain.Day.Duration())
+		if chunkTo.After(to) {
+			chunkTo = to
+		}
+
+		series, err := c.Providers.For(currency).SendTimeSeriesRequest(ctx, currency, chunkFrom, chunkTo)
+		if err != nil {
+			return inserted, fmt.Errorf("send time series request: %w", err)
+		}
+
+		chunkInserted, err := c.HistoryRepo.AddObservations(ctx, currency.ID, series)
+		inserted += chunkInserted
+		if err != nil {
+			return inserted, fmt.Errorf("add observations: %w", err)
+		}
+
+		c.Logger.Info().Msgf("backfilled history, name:%s, from:%s, to:%s, rows:%d",
+			name, chunkFrom.Format(time.DateOnly), chunkTo.Format(time.DateOnly), chunkInserted)
+	}
+
+	// Aggregate the backfilled days of this currency right away, the maintenance job only
+	// downsamples rows that are still inside the raw retention window.
+	for _, resolution := range []domain.Resolution{domain.Hour, domain.Day} {
+		if _, err := c.HistoryRepo.Downsample(ctx, currency.ID, resolution, from, to.Add(domain.Day.Duration())); err != nil {
+			return inserted, fmt.Errorf("downsample %s: %w", resolution, err)
+		}
+	}
+
+	return inserted, nil
+}
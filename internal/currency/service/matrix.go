@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// GetMatrix returns the cross rate of every allowed ordered pair among the requested currencies,
+// or among all currencies when none are requested. All values come from a single read.
+func (c currency) GetMatrix(ctx context.Context, names []string) ([]domain.CrossRate, error) {
+	var (
+		currencies []domain.Currency
+		err        error
+	)
+
+	if len(names) == 0 {
+		currencies, err = c.CurrencyRepo.GetAll(ctx)
+	} else {
+		currencies, err = c.CurrencyRepo.GetCurrenciesByNames(ctx, names)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get currencies: %w", err)
+	}
+
+	if len(names) > 0 && len(currencies) != len(names) {
+		return nil, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	policies, err := c.PairPolicyRepo.GetPairPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get pair policies: %w", err)
+	}
+
+	var rates []domain.CrossRate
+
+	for _, currencyFrom := range currencies {
+		if !currencyFrom.IsAvailable || currencyFrom.ValueUSD.IsZero() {
+			continue
+		}
+
+		for _, currencyTo := range currencies {
+			if currencyFrom.Name == currencyTo.Name || !currencyTo.IsAvailable || currencyTo.ValueUSD.IsZero() {
+				continue
+			}
+
+			if !domain.PairPolicies(policies).IsAllowed(currencyFrom, currencyTo) {
+				continue
+			}
+
+			rates = append(rates, domain.CrossRate{
+				From: currencyFrom.Name,
+				To:   currencyTo.Name,
+				Rate: currencyTo.ValueUSD.DivRound(currencyFrom.ValueUSD, currencyTo.Precision+divisionGuardDigits),
+			})
+		}
+	}
+
+	return rates, nil
+}
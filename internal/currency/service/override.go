@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func (c currency) GetOverrides(ctx context.Context) ([]domain.RateOverride, error) {
+	overrides, err := c.OverrideRepo.GetOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+func (c currency) SetOverride(ctx context.Context, override domain.RateOverride) error {
+	if err := c.OverrideRepo.SetOverride(ctx, override); err != nil {
+		return err
+	}
+
+	c.Logger.Info().Msgf("rate override set, name:%s, value:%s, actor:%s, reason:%s",
+		override.Currency, override.ValueUSD, override.Actor, override.Reason)
+
+	return nil
+}
+
+func (c currency) ClearOverride(ctx context.Context, name, actor, reason string) error {
+	if err := c.OverrideRepo.ClearOverride(ctx, name, actor, reason); err != nil {
+		return err
+	}
+
+	c.Logger.Info().Msgf("rate override cleared, name:%s, actor:%s, reason:%s", name, actor, reason)
+
+	return nil
+}
+
+func (c currency) GetOverrideAudit(ctx context.Context, name string) ([]domain.OverrideAuditEntry, error) {
+	entries, err := c.OverrideRepo.GetOverrideAudit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func (c currency) GetPairPolicies(ctx context.Context) ([]domain.PairPolicy, error) {
+	policies, err := c.PairPolicyRepo.GetPairPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (c currency) SavePairPolicy(ctx context.Context, policy domain.PairPolicy) (int64, error) {
+	id, err := c.PairPolicyRepo.SavePairPolicy(ctx, policy)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (c currency) DeletePairPolicy(ctx context.Context, id int64) error {
+	if err := c.PairPolicyRepo.DeletePairPolicy(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
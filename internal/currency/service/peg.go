@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func (c currency) GetPegs(ctx context.Context) ([]domain.Peg, error) {
+	pegs, err := c.PegRepo.GetPegs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pegs, nil
+}
+
+func (c currency) SetPeg(ctx context.Context, peg domain.Peg) error {
+	if peg.Currency == peg.Target {
+		return domain.NewServiceError(domain.ErrEqualCurrencies, domain.Client)
+	}
+
+	if err := c.PegRepo.SetPeg(ctx, peg); err != nil {
+		return err
+	}
+
+	c.Logger.Info().Msgf("peg set, name:%s, target:%s, tolerance:%s, auto suspend:%t",
+		peg.Currency, peg.Target, peg.Tolerance, peg.AutoSuspend)
+
+	return nil
+}
+
+func (c currency) ClearPeg(ctx context.Context, name string) error {
+	if err := c.PegRepo.ClearPeg(ctx, name); err != nil {
+		return err
+	}
+
+	c.Logger.Info().Msgf("peg cleared, name:%s", name)
+
+	return nil
+}
+
+func (c currency) GetDepegEvents(ctx context.Context, name string) ([]domain.DepegEvent, error) {
+	events, err := c.PegRepo.GetDepegEvents(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// loadPegs returns the pegs evaluated while the currencies of an update are stored.
+// Without them the values are stored without evaluating any peg.
+func (c currency) loadPegs(ctx context.Context) []domain.Peg {
+	pegs, err := c.PegRepo.GetPegs(ctx)
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("get pegs, storing values without checking pegs")
+	}
+
+	return pegs
+}
+
+// updateCurrency stores the value and evaluates the pegs it takes part in, either as the pegged
+// currency or as the target. A currency leaving its band opens a depeg event, suspending conversions
+// through it when its peg asks so, before the value is stored: the value is never served unsuspended.
+// A currency back in its band has its event closed once the value is stored. The pegs are kept
+// up to date with the values and events so the next currencies of the update see them.
+func (c currency) updateCurrency(ctx context.Context, pegs []domain.Peg, update domain.CurrencyUpdateData) error {
+	var affected []*domain.Peg
+
+	for i := range pegs {
+		peg := &pegs[i]
+
+		switch update.Name {
+		case peg.Currency:
+			peg.ValueUSD = update.ValueUSD
+		case peg.Target:
+			peg.TargetValueUSD = update.ValueUSD
+		default:
+			continue
+		}
+
+		if peg.Priced() {
+			affected = append(affected, peg)
+		}
+	}
+
+	for _, peg := range affected {
+		if !peg.InBand() {
+			c.leaveBand(ctx, peg)
+		}
+	}
+
+	if err := c.CurrencyRepo.UpdateCurrencyByName(ctx, update); err != nil {
+		return err
+	}
+
+	for _, peg := range affected {
+		if peg.InBand() && peg.Depegged {
+			c.returnToBand(ctx, peg)
+		}
+	}
+
+	return nil
+}
+
+// leaveBand opens the depeg event of the currency, or raises the deviation of the open one.
+func (c currency) leaveBand(ctx context.Context, peg *domain.Peg) {
+	if peg.Depegged {
+		if err := c.PegRepo.UpdateDepegEvent(ctx, peg.Currency, peg.Deviation()); err != nil {
+			c.Logger.Error().Err(err).Msgf("update depeg event, name:%s", peg.Currency)
+		}
+
+		return
+	}
+
+	if err := c.PegRepo.OpenDepegEvent(ctx, *peg); err != nil {
+		c.Logger.Error().Err(err).Msgf("open depeg event, name:%s", peg.Currency)
+		return
+	}
+
+	peg.Depegged, peg.Suspended = true, peg.AutoSuspend
+
+	c.Logger.Warn().Msgf("currency depegged, name:%s, target:%s, price:%s, tolerance:%s, suspended:%t",
+		peg.Currency, peg.Target, peg.Price(), peg.Tolerance, peg.AutoSuspend)
+}
+
+// returnToBand closes the depeg event of the currency and lifts its suspension.
+func (c currency) returnToBand(ctx context.Context, peg *domain.Peg) {
+	if err := c.PegRepo.CloseDepegEvent(ctx, peg.Currency); err != nil {
+		c.Logger.Error().Err(err).Msgf("close depeg event, name:%s", peg.Currency)
+		return
+	}
+
+	peg.Depegged, peg.Suspended = false, false
+
+	c.Logger.Info().Msgf("currency back on peg, name:%s, target:%s, price:%s", peg.Currency, peg.Target, peg.Price())
+}
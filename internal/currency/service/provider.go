@@ -0,0 +1,237 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ProviderOptions assigns currency types and currencies to a provider
+// and lists in order the providers tried when it fails.
+type ProviderOptions struct {
+	Types      []domain.CurrencyType
+	Currencies []string
+	Fallback   []string
+	Budget     domain.QuotaBudget
+}
+
+// Aggregation tells how the providers of a chain are combined. Without Consensus they are
+// asked in order until one supplies the rate, otherwise all of them are asked at once and
+// the median of the quotes within Tolerance of each other is used.
+type Aggregation struct {
+	Consensus bool
+	Tolerance decimal.Decimal
+}
+
+// ProviderRegistry selects the rates provider of a currency. A provider assigned to the currency
+// wins over the one assigned to its type, everything else is fetched from the default provider.
+type ProviderRegistry struct {
+	providers   map[string]ForexAPI
+	fallback    map[string][]string
+	budgets     map[string]domain.QuotaBudget
+	byCurrency  map[string]string
+	byType      map[domain.CurrencyType]string
+	defaultName string
+	Aggregation Aggregation
+	Quota       domain.QuotaPolicy
+}
+
+func NewProviderRegistry(defaultName string, aggregation Aggregation, quota domain.QuotaPolicy) *ProviderRegistry {
+	return &ProviderRegistry{
+		Aggregation: aggregation,
+		Quota:       quota,
+		providers:   make(map[string]ForexAPI),
+		fallback:    make(map[string][]string),
+		budgets:     make(map[string]domain.QuotaBudget),
+		byCurrency:  make(map[string]string),
+		byType:      make(map[domain.CurrencyType]string),
+		defaultName: defaultName,
+	}
+}
+
+// Register adds the provider and assigns the currency types and currencies to it.
+// A type or a currency can only be assigned to one provider.
+func (r *ProviderRegistry) Register(name string, api ForexAPI, opts ProviderOptions) error {
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("provider %s registered twice", name)
+	}
+
+	for _, tp := range opts.Types {
+		if assigned, ok := r.byType[tp]; ok {
+			return fmt.Errorf("type %s assigned to %s and %s", tp, assigned, name)
+		}
+	}
+
+	for _, currency := range opts.Currencies {
+		if assigned, ok := r.byCurrency[currency]; ok {
+			return fmt.Errorf("currency %s assigned to %s and %s", currency, assigned, name)
+		}
+	}
+
+	r.providers[name] = api
+	r.fallback[name] = opts.Fallback
+	r.budgets[name] = opts.Budget
+
+	for _, tp := range opts.Types {
+		r.byType[tp] = name
+	}
+
+	for _, currency := range opts.Currencies {
+		r.byCurrency[currency] = name
+	}
+
+	return nil
+}
+
+// Validate checks that the default provider and every fallback provider are registered.
+func (r *ProviderRegistry) Validate() error {
+	if _, ok := r.providers[r.defaultName]; !ok {
+		return fmt.Errorf("default provider %s is not registered", r.defaultName)
+	}
+
+	for name, fallback := range r.fallback {
+		for _, fallbackName := range fallback {
+			if _, ok := r.providers[fallbackName]; !ok {
+				return fmt.Errorf("fallback provider %s of %s is not registered", fallbackName, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Name returns the name of the provider the currency is fetched from.
+func (r *ProviderRegistry) Name(currency domain.Currency) string {
+	if name, ok := r.byCurrency[currency.Name]; ok {
+		return name
+	}
+
+	if name, ok := r.byType[currency.Type]; ok {
+		return name
+	}
+
+	return r.defaultName
+}
+
+// Chain returns the names of the provider the currency is fetched from followed by its fallback providers.
+func (r *ProviderRegistry) Chain(currency domain.Currency) []string {
+	return r.ChainOf(r.Name(currency))
+}
+
+// ChainOf returns the name followed by the names of its fallback providers.
+func (r *ProviderRegistry) ChainOf(name string) []string {
+	chain := []string{name}
+	seen := map[string]bool{name: true}
+
+	for _, fallbackName := range r.fallback[name] {
+		if !seen[fallbackName] {
+			seen[fallbackName] = true
+			chain = append(chain, fallbackName)
+		}
+	}
+
+	return chain
+}
+
+// For returns the provider the currency is fetched from.
+func (r *ProviderRegistry) For(currency domain.Currency) ForexAPI {
+	return r.providers[r.Name(currency)]
+}
+
+// Group splits the currencies by the name of their provider, keeping their order.
+func (r *ProviderRegistry) Group(currencies []domain.Currency) map[string][]domain.Currency {
+	groups := make(map[string][]domain.Currency)
+
+	for _, currency := range currencies {
+		name := r.Name(currency)
+		groups[name] = append(groups[name], currency)
+	}
+
+	return groups
+}
+
+// healthReporter is implemented by the providers guarded by a circuit breaker.
+type healthReporter interface {
+	Health() domain.ProviderHealth
+}
+
+// Health returns the state of the providers guarded by a circuit breaker, sorted by name.
+func (r *ProviderRegistry) Health() []domain.ProviderHealth {
+	health := make([]domain.ProviderHealth, 0, len(r.providers))
+
+	for _, api := range r.providers {
+		if reporter, ok := api.(healthReporter); ok {
+			health = append(health, reporter.Health())
+		}
+	}
+
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Provider < health[j].Provider
+	})
+
+	return health
+}
+
+// callCounter is implemented by the providers counting the calls they make.
+type callCounter interface {
+	TakeCalls() int64
+}
+
+// TakeCalls returns the calls made by each provider since the previous call, skipping idle ones.
+func (r *ProviderRegistry) TakeCalls() map[string]int64 {
+	calls := make(map[string]int64)
+
+	for name, api := range r.providers {
+		if counter, ok := api.(callCounter); ok {
+			if count := counter.TakeCalls(); count > 0 {
+				calls[name] = count
+			}
+		}
+	}
+
+	return calls
+}
+
+// callLimiter is implemented by the providers stopping once their budget is used up.
+type callLimiter interface {
+	Limit(remaining int64, limited bool)
+}
+
+// LimitCalls sets the calls each provider has left from its usage,
+// the providers without usage are let through without limit.
+func (r *ProviderRegistry) LimitCalls(usage []domain.QuotaUsage) {
+	byProvider := make(map[string]domain.QuotaUsage, len(usage))
+	for _, u := range usage {
+		byProvider[u.Provider] = u
+	}
+
+	for name, api := range r.providers {
+		if limiter, ok := api.(callLimiter); ok {
+			limiter.Limit(byProvider[name].Remaining())
+		}
+	}
+}
+
+// Budgets returns the call budget of every provider, sorted by name.
+func (r *ProviderRegistry) Budgets() []domain.QuotaUsage {
+	usage := make([]domain.QuotaUsage, 0, len(r.budgets))
+
+	for name, budget := range r.budgets {
+		usage = append(usage, domain.QuotaUsage{Provider: name, Budget: budget})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Provider < usage[j].Provider
+	})
+
+	return usage
+}
+
+// Get returns the provider registered under the name.
+func (r *ProviderRegistry) Get(name string) (ForexAPI, bool) {
+	api, ok := r.providers[name]
+
+	return api, ok
+}
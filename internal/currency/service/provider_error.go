@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// errNotListed is returned for currencies every provider of the chain reported as unknown.
+var errNotListed = errors.New("not listed by any provider")
+
+// disabledProviders holds the providers that rejected the credentials or ran out of quota
+// during an update, they are not asked again until the next one.
+type disabledProviders map[string]error
+
+// check returns the error that disabled the provider, if any.
+func (d disabledProviders) check(name string) error {
+	if err, ok := d[name]; ok {
+		return fmt.Errorf("%s: disabled: %w", name, err)
+	}
+
+	return nil
+}
+
+// record disables the provider when err means it will keep failing.
+func (d disabledProviders) record(name string, err error) {
+	var providerErr *domain.ProviderError
+	if errors.As(err, &providerErr) && providerErr.DisablesProvider() {
+		d[name] = err
+	}
+}
+
+// isUnknownSymbol tells whether the provider error says the currency is not listed by the provider.
+func isUnknownSymbol(err error) bool {
+	kind, _ := domain.ProviderErrorKindOf(err)
+
+	return kind == domain.ProviderUnknownSymbol
+}
+
+func newNotListedErr(currencies []domain.Currency) error {
+	return fmt.Errorf("%w: %s", errNotListed, joinNames(currencies))
+}
+
+func joinNames(currencies []domain.Currency) string {
+	names := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		names = append(names, currency.Name)
+	}
+
+	return strings.Join(names, ",")
+}
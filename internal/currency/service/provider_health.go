@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// SaveProviderHealth stores the circuit breaker state of the providers so the API can report it.
+func (c currency) SaveProviderHealth(ctx context.Context) error {
+	health := c.Providers.Health()
+
+	for _, h := range health {
+		if h.State != domain.BreakerClosed {
+			c.Logger.Warn().Msgf("provider circuit is %s, provider:%s, failures:%d, last error:%s",
+				h.State, h.Provider, h.ConsecutiveFailures, h.LastError)
+		}
+	}
+
+	if err := c.HealthRepo.SaveProviderHealth(ctx, health); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c currency) GetProviderHealth(ctx context.Context) ([]domain.ProviderHealth, error) {
+	health, err := c.HealthRepo.GetProviderHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return health, nil
+}
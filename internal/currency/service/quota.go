@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+// recordCallsTimeout bounds storing the call counts, which happens even when the update ran out of time.
+const recordCallsTimeout = 5 * time.Second
+
+// GetQuota returns the calls made to each provider today and this month along with its budget.
+func (c currency) GetQuota(ctx context.Context) ([]domain.QuotaUsage, error) {
+	usage, err := c.QuotaRepo.GetUsage(ctx, today())
+	if err != nil {
+		return nil, err
+	}
+
+	byProvider := make(map[string]domain.QuotaUsage, len(usage))
+	for _, u := range usage {
+		byProvider[u.Provider] = u
+	}
+
+	budgets := c.Providers.Budgets()
+	for i := range budgets {
+		budgets[i].Daily = byProvider[budgets[i].Provider].Daily
+		budgets[i].Monthly = byProvider[budgets[i].Provider].Monthly
+	}
+
+	return budgets, nil
+}
+
+// applyQuota disables the providers whose budget is exhausted, limits the calls of the others
+// to what is left and returns the currencies to refresh now.
+// Currencies of a provider running low on budget are skipped when their priority is below the policy
+// minimum or when they were refreshed less than the low budget refresh interval ago.
+func (c currency) applyQuota(ctx context.Context, currencies []domain.Currency, disabled disabledProviders) []domain.Currency {
+	usage, err := c.GetQuota(ctx)
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("get quota usage, refreshing without budget limits")
+		c.Providers.LimitCalls(nil)
+
+		return currencies
+	}
+
+	c.Providers.LimitCalls(usage)
+
+	policy := c.Providers.Quota
+	levels := make(map[string]domain.QuotaLevel, len(usage))
+
+	for _, u := range usage {
+		level := u.Level(policy.LowWatermarkPercent)
+		levels[u.Provider] = level
+
+		switch level {
+		case domain.QuotaExhausted:
+			c.Logger.Warn().Msgf("provider budget exhausted, provider:%s, daily:%d/%d, monthly:%d/%d",
+				u.Provider, u.Daily, u.Budget.Daily, u.Monthly, u.Budget.Monthly)
+			disabled[u.Provider] = domain.NewProviderError(u.Provider, domain.ProviderQuotaExceeded, 0, "call budget exhausted")
+		case domain.QuotaLow:
+			c.Logger.Warn().Msgf("provider budget running low, provider:%s, daily:%d/%d, monthly:%d/%d",
+				u.Provider, u.Daily, u.Budget.Daily, u.Monthly, u.Budget.Monthly)
+		}
+	}
+
+	now := time.Now()
+	kept := make([]domain.Currency, 0, len(currencies))
+
+	for _, currency := range currencies {
+		if levels[c.Providers.Name(currency)] == domain.QuotaLow &&
+			(currency.Priority < policy.MinPriority || now.Sub(currency.UpdatedAt) < policy.LowRefreshInterval) {
+			continue
+		}
+
+		kept = append(kept, currency)
+	}
+
+	if skipped := len(currencies) - len(kept); skipped > 0 {
+		c.Logger.Info().Msgf("skipped currencies to save provider budget, skipped:%d", skipped)
+	}
+
+	return kept
+}
+
+// limitCalls limits the calls of every provider to what is left of its budget.
+func (c currency) limitCalls(ctx context.Context) {
+	usage, err := c.GetQuota(ctx)
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("get quota usage, calling providers without budget limits")
+	}
+
+	c.Providers.LimitCalls(usage)
+}
+
+// recordCalls adds the calls made by the providers since the previous record to their usage.
+func (c currency) recordCalls(ctx context.Context) {
+	calls := c.Providers.TakeCalls()
+	if len(calls) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordCallsTimeout)
+	defer cancel()
+
+	if err := c.QuotaRepo.AddCalls(ctx, today(), calls); err != nil {
+		c.Logger.Error().Err(err).Msgf("record provider calls, calls:%v", calls)
+	}
+}
+
+func today() time.Time {
+	return time.Now().UTC().Truncate(domain.Day.Duration())
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/alemax1/currencies-api/pkg/logger"
+)
+
+type QuoteRepo interface {
+	CreateQuote(ctx context.Context, quote domain.Quote, ttl time.Duration) (domain.Quote, error)
+	AcceptQuote(ctx context.Context, id string) (domain.Quote, error)
+}
+
+type quote struct {
+	QuoteRepo QuoteRepo
+	Currency  *currency
+	TTL       time.Duration
+	Logger    logger.Logger
+}
+
+func newQuote(
+	quoteRepo QuoteRepo,
+	currencySvc *currency,
+	ttl time.Duration,
+	logger logger.Logger,
+) *quote {
+	return &quote{
+		QuoteRepo: quoteRepo,
+		Currency:  currencySvc,
+		TTL:       ttl,
+		Logger:    logger,
+	}
+}
+
+// Create converts the value at the current rates and locks the result for the quote TTL.
+func (q quote) Create(ctx context.Context, rate domain.Rate) (domain.Quote, error) {
+	rate.At = time.Time{}
+
+	conversion, err := q.Currency.GetRate(ctx, rate)
+	if err != nil {
+		return domain.Quote{}, fmt.Errorf("get rate: %w", err)
+	}
+
+	created, err := q.QuoteRepo.CreateQuote(ctx, domain.Quote{
+		From:       rate.From,
+		To:         rate.To,
+		Value:      rate.Value,
+		Conversion: conversion,
+	}, q.TTL)
+	if err != nil {
+		return domain.Quote{}, fmt.Errorf("create quote: %w", err)
+	}
+
+	return created, nil
+}
+
+// Accept returns the locked conversion of a quote that has not expired yet.
+func (q quote) Accept(ctx context.Context, id string) (domain.Quote, error) {
+	accepted, err := q.QuoteRepo.AcceptQuote(ctx, id)
+	if err != nil {
+		return domain.Quote{}, err
+	}
+
+	return accepted, nil
+}
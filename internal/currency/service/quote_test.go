@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+	"github.com/rs/zerolog"
+)
+
+// fakeQuoteRepo keeps the quotes in memory and measures their expiry with now.
+type fakeQuoteRepo struct {
+	now    time.Time
+	ttl    time.Duration
+	quotes map[string]domain.Quote
+}
+
+func (f *fakeQuoteRepo) CreateQuote(_ context.Context, quote domain.Quote, ttl time.Duration) (domain.Quote, error) {
+	f.ttl = ttl
+
+	quote.ID = fmt.Sprintf("quote-%d", len(f.quotes)+1)
+	quote.ExpiresAt = f.now.Add(ttl)
+	f.quotes[quote.ID] = quote
+
+	return quote, nil
+}
+
+func (f *fakeQuoteRepo) AcceptQuote(_ context.Context, id string) (domain.Quote, error) {
+	quote, ok := f.quotes[id]
+	if !ok {
+		return domain.Quote{}, domain.NewServiceError(domain.ErrNothingFound, domain.Client)
+	}
+
+	if !f.now.Before(quote.ExpiresAt) {
+		return domain.Quote{}, domain.NewServiceError(domain.ErrQuoteExpired, domain.Client)
+	}
+
+	if quote.AcceptedAt.IsZero() {
+		quote.AcceptedAt = f.now
+		f.quotes[id] = quote
+	}
+
+	return quote, nil
+}
+
+func (f fakeCurrencyRepo) GetCurrencyAt(context.Context, string, time.Time) (domain.Currency, error) {
+	return domain.Currency{}, domain.NewServiceError(domain.ErrNoRateAtTime, domain.Client)
+}
+
+func newTestQuote(ttl time.Duration) (*quote, *fakeQuoteRepo) {
+	nop := zerolog.Nop()
+	repo := &fakeQuoteRepo{
+		now:    time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC),
+		quotes: make(map[string]domain.Quote),
+	}
+
+	currencySvc := newConversionCurrency(nil, []domain.FeeSchedule{
+		{From: "USD", To: "EUR", Fixed: dec("1"), Percentage: dec("0.01")},
+	})
+
+	return newQuote(repo, currencySvc, ttl, &nop), repo
+}
+
+func TestQuoteCreateAndAccept(t *testing.T) {
+	ttl := 30 * time.Second
+	q, repo := newTestQuote(ttl)
+	ctx := context.Background()
+
+	// A quote always locks the current rates, even when asked for a past instant.
+	created, err := q.Create(ctx, domain.Rate{From: "USD", To: "EUR", Value: dec("100"), At: repo.now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if repo.ttl != ttl {
+		t.Errorf("got ttl %s, want %s", repo.ttl, ttl)
+	}
+
+	if !created.ExpiresAt.Equal(repo.now.Add(ttl)) {
+		t.Errorf("got expiry %s, want %s", created.ExpiresAt, repo.now.Add(ttl))
+	}
+
+	if !created.Conversion.Amount.Equal(dec("50")) || !created.Conversion.Fee.Total.Equal(dec("1.5")) ||
+		!created.Conversion.Net.Equal(dec("48.5")) {
+		t.Errorf("got conversion %+v, want 50 less a 1.5 fee", created.Conversion)
+	}
+
+	repo.now = repo.now.Add(ttl - time.Second)
+
+	accepted, err := q.Accept(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	if !accepted.AcceptedAt.Equal(repo.now) {
+		t.Errorf("got accepted at %s, want %s", accepted.AcceptedAt, repo.now)
+	}
+
+	if !accepted.Conversion.Net.Equal(created.Conversion.Net) || !accepted.Conversion.Fee.Fixed.Equal(created.Conversion.Fee.Fixed) {
+		t.Errorf("got conversion %+v, want the locked %+v", accepted.Conversion, created.Conversion)
+	}
+
+	firstAcceptedAt := accepted.AcceptedAt
+	repo.now = repo.now.Add(500 * time.Millisecond)
+
+	if again, err := q.Accept(ctx, created.ID); err != nil || !again.AcceptedAt.Equal(firstAcceptedAt) {
+		t.Errorf("got %s, %v accepting again, want the first acceptance %s", again.AcceptedAt, err, firstAcceptedAt)
+	}
+
+	repo.now = created.ExpiresAt
+
+	if _, err := q.Accept(ctx, created.ID); serviceErrMessage(err) != domain.ErrQuoteExpired {
+		t.Errorf("got %v after expiry, want %s", err, domain.ErrQuoteExpired)
+	}
+
+	if _, err := q.Accept(ctx, "unknown"); serviceErrMessage(err) != domain.ErrNothingFound {
+		t.Errorf("got %v for an unknown quote, want %s", err, domain.ErrNothingFound)
+	}
+}
+
+func TestQuoteCreateBelowFee(t *testing.T) {
+	q, repo := newTestQuote(time.Minute)
+
+	_, err := q.Create(context.Background(), domain.Rate{From: "USD", To: "EUR", Value: dec("1")})
+	if serviceErrMessage(err) != domain.ErrAmountBelowFee {
+		t.Fatalf("got %v, want %s", err, domain.ErrAmountBelowFee)
+	}
+
+	if len(repo.quotes) != 0 {
+		t.Errorf("got %d quotes stored, want none", len(repo.quotes))
+	}
+}
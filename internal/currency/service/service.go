@@ -4,36 +4,67 @@ import (
 	"context"
 	"time"
 
+	"github.com/alemax1/currencies-api/config"
+	"github.com/alemax1/currencies-api/internal/currency/domain"
 	"github.com/alemax1/currencies-api/pkg/logger"
 )
 
 type Service struct {
 	Currency *currency
+	Quote    *quote
+}
+
+// Repos groups the storage dependencies of the services.
+type Repos struct {
+	CurrencyRepo   CurrencyRepo
+	HistoryRepo    HistoryRepo
+	PairPolicyRepo PairPolicyRepo
+	QuoteRepo      QuoteRepo
+	SpreadRepo     SpreadRepo
+	FeeRepo        FeeRepo
+	OverrideRepo   OverrideRepo
+	HealthRepo     ProviderHealthRepo
+	QuotaRepo      QuotaRepo
+	SymbolRepo     SymbolRepo
+	PegRepo        PegRepo
 }
 
 func New(
-	CurrencyRepo CurrencyRepo,
-	ForexAPI ForexAPI,
+	repos Repos,
+	providers *ProviderRegistry,
+	conversionCfg config.Conversion,
 	logger logger.Logger,
 ) *Service {
 	currencySvc := newCurrency(
-		CurrencyRepo,
-		ForexAPI,
+		repos,
+		providers,
+		domain.RoundingMode(conversionCfg.RoundingMode),
 		logger,
 	)
 
+	quoteSvc := newQuote(
+		repos.QuoteRepo,
+		currencySvc,
+		conversionCfg.QuoteTTL,
+		logger,
+	)
+
+	return &Service{
+		Currency: currencySvc,
+		Quote:    quoteSvc,
+	}
+}
+
+// WarmUp refreshes every currency in the background so rates are available before the first worker tick.
+func (s *Service) WarmUp() {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		if err := currencySvc.UpdateCryptoCurrencies(ctx); err != nil {
-			currencySvc.Logger.Error().Err(err).Msgf("update crypto currencies")
+		if err := s.Currency.UpdateCryptoCurrencies(ctx); err != nil {
+			s.Currency.Logger.Error().Err(err).Msgf("update crypto currencies")
 		}
-		if err := currencySvc.UpdateFiatCurrencies(ctx); err != nil {
-			currencySvc.Logger.Error().Err(err).Msgf("update fiat currencies")
+		if err := s.Currency.UpdateFiatCurrencies(ctx); err != nil {
+			s.Currency.Logger.Error().Err(err).Msgf("update fiat currencies")
 		}
 	}()
-
-	return &Service{
-		Currency: currencySvc,
-	}
 }
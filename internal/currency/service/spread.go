@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func (c currency) GetSpreads(ctx context.Context) ([]domain.Spread, error) {
+	spreads, err := c.SpreadRepo.GetSpreads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return spreads, nil
+}
+
+func (c currency) SaveSpread(ctx context.Context, spread domain.Spread) (int64, error) {
+	id, err := c.SpreadRepo.SaveSpread(ctx, spread)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (c currency) DeleteSpread(ctx context.Context, id int64) error {
+	if err := c.SpreadRepo.DeleteSpread(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
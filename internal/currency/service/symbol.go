@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alemax1/currencies-api/internal/currency/domain"
+)
+
+func (c currency) GetProviderSymbols(ctx context.Context, provider string) ([]domain.ProviderSymbol, error) {
+	symbols, err := c.SymbolRepo.GetProviderSymbols(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return symbols, nil
+}
+
+// SetProviderSymbol maps the currency to the symbol of a configured provider.
+// The providers pick the change up on their next symbols refresh.
+func (c currency) SetProviderSymbol(ctx context.Context, symbol domain.ProviderSymbol) error {
+	if _, ok := c.Providers.Get(symbol.Provider); !ok {
+		return domain.NewServiceError(domain.ErrUnknownProvider, domain.Client)
+	}
+
+	if err := c.SymbolRepo.SetProviderSymbol(ctx, symbol); err != nil {
+		return err
+	}
+
+	c.Logger.Info().Msgf("provider symbol set, provider:%s, name:%s, symbol:%s", symbol.Provider, symbol.Currency, symbol.Symbol)
+
+	return nil
+}
+
+func (c currency) DeleteProviderSymbol(ctx context.Context, provider, name string) error {
+	if err := c.SymbolRepo.DeleteProviderSymbol(ctx, provider, name); err != nil {
+		return err
+	}
+
+	c.Logger.Info().Msgf("provider symbol deleted, provider:%s, name:%s", provider, name)
+
+	return nil
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS currency_rates_history(
+    id BIGSERIAL PRIMARY KEY,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    value_usd DECIMAL NOT NULL,
+    provider VARCHAR NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS currency_rates_history_currency_id_fetched_at_idx
+    ON currency_rates_history(currency_id, fetched_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS currency_rates_aggregates;
+
+DROP TYPE IF EXISTS rate_resolutions;
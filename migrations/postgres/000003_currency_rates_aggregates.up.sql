@@ -0,0 +1,14 @@
+CREATE TYPE rate_resolutions AS ENUM ('hour', 'day');
+
+CREATE TABLE IF NOT EXISTS currency_rates_aggregates(
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    resolution rate_resolutions NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    open DECIMAL NOT NULL,
+    high DECIMAL NOT NULL,
+    low DECIMAL NOT NULL,
+    close DECIMAL NOT NULL,
+    samples INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency_id, resolution, bucket_start)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS currency_rates_history_currency_id_provider_fetched_at_idx;
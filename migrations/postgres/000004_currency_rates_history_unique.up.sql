@@ -0,0 +1,2 @@
+CREATE UNIQUE INDEX IF NOT EXISTS currency_rates_history_currency_id_provider_fetched_at_idx
+    ON currency_rates_history(currency_id, provider, fetched_at);
@@ -0,0 +1,5 @@
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS precision SMALLINT NOT NULL DEFAULT 2
+    CHECK (precision >= 0 AND precision <= 30);
+
+UPDATE currencies SET precision=6 WHERE name IN ('USDT', 'USDC');
+UPDATE currencies SET precision=18 WHERE name='ETH';
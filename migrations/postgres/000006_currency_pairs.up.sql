@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS currency_pairs(
+    id SERIAL PRIMARY KEY,
+    from_name VARCHAR REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    to_name VARCHAR REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    from_type currency_types,
+    to_type currency_types,
+    is_allowed BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (
+        (from_name IS NOT NULL AND to_name IS NOT NULL AND from_type IS NULL AND to_type IS NULL)
+        OR (from_name IS NULL AND to_name IS NULL AND from_type IS NOT NULL AND to_type IS NOT NULL)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS currency_pairs_names_idx
+    ON currency_pairs(from_name, to_name) WHERE from_name IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS currency_pairs_types_idx
+    ON currency_pairs(from_type, to_type) WHERE from_type IS NOT NULL;
+
+-- INIT DATA
+INSERT INTO currency_pairs(from_type, to_type, is_allowed) VALUES('fiat', 'crypto', true);
+INSERT INTO currency_pairs(from_type, to_type, is_allowed) VALUES('crypto', 'fiat', true);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS quotes(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_name VARCHAR NOT NULL,
+    to_name VARCHAR NOT NULL,
+    value DECIMAL NOT NULL,
+    amount DECIMAL NOT NULL,
+    precision SMALLINT NOT NULL,
+    rounding VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS quotes_expires_at_idx ON quotes(expires_at);
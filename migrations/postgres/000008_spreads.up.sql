@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS spreads(
+    id SERIAL PRIMARY KEY,
+    from_name VARCHAR REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    to_name VARCHAR REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    currency_name VARCHAR REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    from_type currency_types,
+    to_type currency_types,
+    markup DECIMAL NOT NULL CHECK (markup >= 0 AND markup < 1),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (
+        (from_name IS NOT NULL AND to_name IS NOT NULL AND currency_name IS NULL AND from_type IS NULL AND to_type IS NULL)
+        OR (from_name IS NULL AND to_name IS NULL AND currency_name IS NOT NULL AND from_type IS NULL AND to_type IS NULL)
+        OR (from_name IS NULL AND to_name IS NULL AND currency_name IS NULL AND from_type IS NOT NULL AND to_type IS NOT NULL)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS spreads_names_idx
+    ON spreads(from_name, to_name) WHERE from_name IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS spreads_currency_idx
+    ON spreads(currency_name) WHERE currency_name IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS spreads_types_idx
+    ON spreads(from_type, to_type) WHERE from_type IS NOT NULL;
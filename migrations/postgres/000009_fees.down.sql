@@ -0,0 +1,6 @@
+ALTER TABLE quotes DROP COLUMN IF EXISTS net;
+ALTER TABLE quotes DROP COLUMN IF EXISTS fee;
+
+DROP TABLE IF EXISTS fee_tiers;
+
+DROP TABLE IF EXISTS fee_schedules;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS fee_schedules(
+    id SERIAL PRIMARY KEY,
+    from_name VARCHAR NOT NULL REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    to_name VARCHAR NOT NULL REFERENCES currencies(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    fixed DECIMAL NOT NULL DEFAULT 0 CHECK (fixed >= 0),
+    percentage DECIMAL NOT NULL DEFAULT 0 CHECK (percentage >= 0 AND percentage < 1),
+    min_fee DECIMAL CHECK (min_fee >= 0),
+    max_fee DECIMAL CHECK (max_fee >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (from_name, to_name)
+);
+
+CREATE TABLE IF NOT EXISTS fee_tiers(
+    id SERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES fee_schedules(id) ON DELETE CASCADE,
+    from_amount DECIMAL NOT NULL CHECK (from_amount >= 0),
+    fixed DECIMAL NOT NULL DEFAULT 0 CHECK (fixed >= 0),
+    percentage DECIMAL NOT NULL DEFAULT 0 CHECK (percentage >= 0 AND percentage < 1),
+    UNIQUE (schedule_id, from_amount)
+);
+
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS fee DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS net DECIMAL;
+UPDATE quotes SET net=amount;
+ALTER TABLE quotes ALTER COLUMN net SET NOT NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS rate_overrides_audit;
+
+DROP TABLE IF EXISTS rate_overrides;
+
+DROP TYPE IF EXISTS rate_override_actions;
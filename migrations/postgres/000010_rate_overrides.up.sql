@@ -0,0 +1,24 @@
+CREATE TYPE rate_override_actions AS ENUM ('set', 'clear');
+
+CREATE TABLE IF NOT EXISTS rate_overrides(
+    currency_id INTEGER PRIMARY KEY REFERENCES currencies(id) ON DELETE CASCADE,
+    value_usd DECIMAL NOT NULL CHECK (value_usd > 0),
+    reason VARCHAR NOT NULL,
+    set_by VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rate_overrides_audit(
+    id SERIAL PRIMARY KEY,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    action rate_override_actions NOT NULL,
+    value_usd DECIMAL,
+    reason VARCHAR NOT NULL,
+    actor VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS rate_overrides_audit_currency_idx
+    ON rate_overrides_audit(currency_id, created_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS provider VARCHAR;
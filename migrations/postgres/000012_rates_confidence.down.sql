@@ -0,0 +1,2 @@
+ALTER TABLE currency_rates_history DROP COLUMN IF EXISTS confidence;
+ALTER TABLE currencies DROP COLUMN IF EXISTS confidence;
@@ -0,0 +1,2 @@
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS confidence DECIMAL CHECK (confidence >= 0 AND confidence <= 1);
+ALTER TABLE currency_rates_history ADD COLUMN IF NOT EXISTS confidence DECIMAL CHECK (confidence >= 0 AND confidence <= 1);
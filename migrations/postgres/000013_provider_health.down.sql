@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS provider_health;
+
+DROP TYPE IF EXISTS breaker_states;
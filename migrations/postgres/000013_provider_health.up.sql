@@ -0,0 +1,14 @@
+CREATE TYPE breaker_states AS ENUM ('closed', 'open', 'half_open');
+
+CREATE TABLE IF NOT EXISTS provider_health(
+    provider VARCHAR PRIMARY KEY,
+    state breaker_states NOT NULL,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_latency_ms BIGINT NOT NULL DEFAULT 0,
+    avg_latency_ms BIGINT NOT NULL DEFAULT 0,
+    last_error VARCHAR NOT NULL DEFAULT '',
+    last_success_at TIMESTAMPTZ,
+    last_failure_at TIMESTAMPTZ,
+    opened_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
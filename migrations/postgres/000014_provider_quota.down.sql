@@ -0,0 +1,3 @@
+ALTER TABLE currencies DROP COLUMN IF EXISTS priority;
+
+DROP TABLE IF EXISTS provider_calls;
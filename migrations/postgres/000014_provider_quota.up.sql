@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS provider_calls(
+    provider VARCHAR NOT NULL,
+    day DATE NOT NULL,
+    calls BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (provider, day)
+);
+
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS provider_symbols(
+    provider VARCHAR NOT NULL,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    symbol VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, currency_id)
+);
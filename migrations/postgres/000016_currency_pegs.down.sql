@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS depeg_events;
+
+ALTER TABLE currencies DROP COLUMN IF EXISTS is_suspended;
+ALTER TABLE currencies DROP COLUMN IF EXISTS peg_auto_suspend;
+ALTER TABLE currencies DROP COLUMN IF EXISTS peg_tolerance;
+ALTER TABLE currencies DROP COLUMN IF EXISTS peg_currency;
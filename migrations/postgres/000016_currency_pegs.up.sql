@@ -0,0 +1,22 @@
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS peg_currency VARCHAR;
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS peg_tolerance DECIMAL CHECK (peg_tolerance >= 0);
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS peg_auto_suspend BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS is_suspended BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS depeg_events(
+    id SERIAL PRIMARY KEY,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    peg_currency VARCHAR NOT NULL,
+    tolerance DECIMAL NOT NULL,
+    start_price DECIMAL NOT NULL,
+    max_deviation DECIMAL NOT NULL,
+    suspended BOOLEAN NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS depeg_events_open_idx ON depeg_events(currency_id) WHERE ended_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS depeg_events_currency_idx ON depeg_events(currency_id, started_at DESC);
+
+UPDATE currencies SET peg_currency='USD', peg_tolerance=0.01 WHERE name IN ('USDT', 'USDC');
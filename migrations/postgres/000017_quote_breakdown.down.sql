@@ -0,0 +1,7 @@
+ALTER TABLE quotes DROP COLUMN IF EXISTS markup;
+ALTER TABLE quotes DROP COLUMN IF EXISTS ask;
+ALTER TABLE quotes DROP COLUMN IF EXISTS bid;
+ALTER TABLE quotes DROP COLUMN IF EXISTS mid;
+ALTER TABLE quotes DROP COLUMN IF EXISTS fee_cap_adjustment;
+ALTER TABLE quotes DROP COLUMN IF EXISTS fee_percentage;
+ALTER TABLE quotes DROP COLUMN IF EXISTS fee_fixed;
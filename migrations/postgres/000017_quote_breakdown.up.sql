@@ -0,0 +1,10 @@
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS fee_fixed DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS fee_percentage DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS fee_cap_adjustment DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS mid DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS bid DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS ask DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS markup DECIMAL NOT NULL DEFAULT 0;
+
+-- the breakdown of older quotes is unknown, their whole fee is kept as fixed so the lines still sum to it
+UPDATE quotes SET fee_fixed=fee;
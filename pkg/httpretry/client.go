@@ -0,0 +1,130 @@
+package httpretry
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Config limits the retries. Attempts counts the first request, delays grow
+// exponentially from BaseDelay and never exceed MaxDelay.
+type Config struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Client retries requests failing with a transport error, 429 or a 5xx gateway status.
+// OnAttempt, when set, is called before every request sent, retries included.
+type Client struct {
+	HTTP      *http.Client
+	Cfg       Config
+	OnAttempt func()
+}
+
+func New(httpClient *http.Client, cfg Config) *Client {
+	return &Client{
+		HTTP: httpClient,
+		Cfg:  cfg,
+	}
+}
+
+// Do sends the request until it succeeds, the attempts run out or the request context is done.
+// Between attempts it waits for the Retry-After of a 429 or 503 response, otherwise for a jittered
+// exponential backoff. It gives up early when the wait would outlast the context deadline and
+// returns the last response or error. Only requests without a body can be retried.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if c.OnAttempt != nil {
+			c.OnAttempt()
+		}
+
+		resp, err := c.HTTP.Do(req.Clone(ctx))
+		if attempt >= c.Cfg.Attempts || req.Body != nil || ctx.Err() != nil || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp); ok {
+			delay = retryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random delay between the half and the whole of the capped exponential delay of the attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.Cfg.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if exp := c.Cfg.BaseDelay << shift; exp > 0 && exp < delay {
+			delay = exp
+		}
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+
+	return half + rand.N(half+1)
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter reads the Retry-After header of a 429 or 503 response, given either in seconds or as a date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package httpretry
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	client := New(http.DefaultClient, Config{Attempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{attempt: 1, ceiling: 100 * time.Millisecond},
+		{attempt: 2, ceiling: 200 * time.Millisecond},
+		{attempt: 4, ceiling: 800 * time.Millisecond},
+		{attempt: 5, ceiling: time.Second},
+		{attempt: 40, ceiling: time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if got := client.backoff(tt.attempt); got < tt.ceiling/2 || got > tt.ceiling {
+				t.Fatalf("attempt %d: got %s, want between %s and %s", tt.attempt, got, tt.ceiling/2, tt.ceiling)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", status: http.StatusTooManyRequests, header: "3", want: 3 * time.Second, wantOK: true},
+		{name: "zero seconds", status: http.StatusServiceUnavailable, header: "0", want: 0, wantOK: true},
+		{name: "past date", status: http.StatusTooManyRequests, header: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "negative seconds", status: http.StatusTooManyRequests, header: "-1"},
+		{name: "garbage", status: http.StatusTooManyRequests, header: "soon"},
+		{name: "missing", status: http.StatusTooManyRequests},
+		{name: "other status", status: http.StatusBadGateway, header: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+
+			got, ok := parseRetryAfter(resp)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("got %s %v, want %s %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+		got, ok := parseRetryAfter(resp)
+		if !ok || got <= 58*time.Second || got > time.Minute {
+			t.Errorf("got %s %v, want about a minute", got, ok)
+		}
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		if _, ok := parseRetryAfter(nil); ok {
+			t.Error("got a delay without a response")
+		}
+	})
+}